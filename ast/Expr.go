@@ -6,70 +6,101 @@ import (
 )
 
 type ExprVisitor interface {
-	VisitBinary(obj Binary) (Object, error)
-	VisitCall(obj Call) (Object, error)
-	VisitGrouping(obj Grouping) (Object, error)
-	VisitLiteral(obj Literal) (Object, error)
-	VisitLogical(obj Logical) (Object, error)
-	VisitUnary(obj Unary) (Object, error)
-	VisitVariable(obj Variable) (Object, error)
-	VisitAssign(obj Assign) (Object, error)
+	VisitOptionalChain(obj *OptionalChain) (Object, error)
+	VisitSetSubscript(obj *SetSubscript) (Object, error)
+	VisitThis(obj *This) (Object, error)
+	VisitAssign(obj *Assign) (Object, error)
+	VisitGet(obj *Get) (Object, error)
+	VisitOptional(obj *Optional) (Object, error)
+	VisitSubscript(obj *Subscript) (Object, error)
+	VisitUnary(obj *Unary) (Object, error)
+	VisitUpdate(obj *Update) (Object, error)
+	VisitInterpolation(obj *Interpolation) (Object, error)
+	VisitMap(obj *Map) (Object, error)
+	VisitCall(obj *Call) (Object, error)
+	VisitGrouping(obj *Grouping) (Object, error)
+	VisitLiteral(obj *Literal) (Object, error)
+	VisitLogical(obj *Logical) (Object, error)
+	VisitSet(obj *Set) (Object, error)
+	VisitSuper(obj *Super) (Object, error)
+	VisitVariable(obj *Variable) (Object, error)
+	VisitAssignPattern(obj *AssignPattern) (Object, error)
+	VisitBinary(obj *Binary) (Object, error)
+	VisitConditional(obj *Conditional) (Object, error)
+	VisitLambda(obj *Lambda) (Object, error)
+	VisitList(obj *List) (Object, error)
 }
 
 type Expr interface{
 	Accept(v ExprVisitor) (Object, error)
 }
 
+type Subscript struct {
+	Object Expr
+	Bracket Token
+	Index Expr
+}
+
+func NewSubscript(Object Expr, Bracket Token, Index Expr) *Subscript {
+	return &Subscript{Object, Bracket, Index,}
+}
+
+func (obj *Subscript) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitSubscript(obj)
+}
+
 type Unary struct {
 	Operator Token
 	Right Expr
 }
 
-func NewUnary(Operator Token, Right Expr) Unary {
-	return Unary{Operator, Right,}
+func NewUnary(Operator Token, Right Expr) *Unary {
+	return &Unary{Operator, Right,}
 }
 
-func (obj Unary) Accept(v ExprVisitor) (Object, error) {
+func (obj *Unary) Accept(v ExprVisitor) (Object, error) {
 	return v.VisitUnary(obj)
 }
 
-type Variable struct {
-	Name Token
+type Update struct {
+	Target Expr
+	Operator Token
+	Value Expr
+	Postfix bool
 }
 
-func NewVariable(Name Token) Variable {
-	return Variable{Name,}
+func NewUpdate(Target Expr, Operator Token, Value Expr, Postfix bool) *Update {
+	return &Update{Target, Operator, Value, Postfix,}
 }
 
-func (obj Variable) Accept(v ExprVisitor) (Object, error) {
-	return v.VisitVariable(obj)
+func (obj *Update) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitUpdate(obj)
 }
 
-type Assign struct {
-	Name Token
-	Value Expr
+type Interpolation struct {
+	Parts []Expr
 }
 
-func NewAssign(Name Token, Value Expr) Assign {
-	return Assign{Name, Value,}
+func NewInterpolation(Parts []Expr) *Interpolation {
+	return &Interpolation{Parts,}
 }
 
-func (obj Assign) Accept(v ExprVisitor) (Object, error) {
-	return v.VisitAssign(obj)
+func (obj *Interpolation) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitInterpolation(obj)
 }
 
-type Binary struct {
-	Left Expr
-	Operator Token
-	Right Expr
+type Map struct {
+	Brace Token
+	Keys []Expr
+	Values []Expr
 }
 
-func NewBinary(Left Expr, Operator Token, Right Expr) Binary {
-	return Binary{Left, Operator, Right,}
+func NewMap(Brace Token, Keys []Expr, Values []Expr) *Map {
+	return &Map{Brace, Keys, Values,}
 }
 
-func (obj Binary) Accept(v ExprVisitor) (Object, error) {
-	return v.VisitBinary(obj)
+func (obj *Map) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitMap(obj)
 }
 
 type Call struct {
@@ -78,11 +109,11 @@ type Call struct {
 	Arguments []Expr
 }
 
-func NewCall(Callee Expr, Paren Token, Arguments []Expr) Call {
-	return Call{Callee, Paren, Arguments,}
+func NewCall(Callee Expr, Paren Token, Arguments []Expr) *Call {
+	return &Call{Callee, Paren, Arguments,}
 }
 
-func (obj Call) Accept(v ExprVisitor) (Object, error) {
+func (obj *Call) Accept(v ExprVisitor) (Object, error) {
 	return v.VisitCall(obj)
 }
 
@@ -90,11 +121,11 @@ type Grouping struct {
 	Expression Expr
 }
 
-func NewGrouping(Expression Expr) Grouping {
-	return Grouping{Expression,}
+func NewGrouping(Expression Expr) *Grouping {
+	return &Grouping{Expression,}
 }
 
-func (obj Grouping) Accept(v ExprVisitor) (Object, error) {
+func (obj *Grouping) Accept(v ExprVisitor) (Object, error) {
 	return v.VisitGrouping(obj)
 }
 
@@ -102,11 +133,11 @@ type Literal struct {
 	Value Object
 }
 
-func NewLiteral(Value Object) Literal {
-	return Literal{Value,}
+func NewLiteral(Value Object) *Literal {
+	return &Literal{Value,}
 }
 
-func (obj Literal) Accept(v ExprVisitor) (Object, error) {
+func (obj *Literal) Accept(v ExprVisitor) (Object, error) {
 	return v.VisitLiteral(obj)
 }
 
@@ -116,11 +147,196 @@ type Logical struct {
 	Right Expr
 }
 
-func NewLogical(Left Expr, Operator Token, Right Expr) Logical {
-	return Logical{Left, Operator, Right,}
+func NewLogical(Left Expr, Operator Token, Right Expr) *Logical {
+	return &Logical{Left, Operator, Right,}
 }
 
-func (obj Logical) Accept(v ExprVisitor) (Object, error) {
+func (obj *Logical) Accept(v ExprVisitor) (Object, error) {
 	return v.VisitLogical(obj)
 }
 
+type Set struct {
+	Object Expr
+	Name Token
+	Value Expr
+}
+
+func NewSet(Object Expr, Name Token, Value Expr) *Set {
+	return &Set{Object, Name, Value,}
+}
+
+func (obj *Set) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitSet(obj)
+}
+
+type Super struct {
+	Keyword Token
+	Method Token
+}
+
+func NewSuper(Keyword Token, Method Token) *Super {
+	return &Super{Keyword, Method,}
+}
+
+func (obj *Super) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitSuper(obj)
+}
+
+type Variable struct {
+	Name Token
+}
+
+func NewVariable(Name Token) *Variable {
+	return &Variable{Name,}
+}
+
+func (obj *Variable) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitVariable(obj)
+}
+
+type AssignPattern struct {
+	Opening Token
+	Targets []Expr
+	Rest Expr
+	Value Expr
+}
+
+func NewAssignPattern(Opening Token, Targets []Expr, Rest Expr, Value Expr) *AssignPattern {
+	return &AssignPattern{Opening, Targets, Rest, Value,}
+}
+
+func (obj *AssignPattern) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitAssignPattern(obj)
+}
+
+type Binary struct {
+	Left Expr
+	Operator Token
+	Right Expr
+}
+
+func NewBinary(Left Expr, Operator Token, Right Expr) *Binary {
+	return &Binary{Left, Operator, Right,}
+}
+
+func (obj *Binary) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitBinary(obj)
+}
+
+type Conditional struct {
+	Condition Expr
+	ThenBranch Expr
+	ElseBranch Expr
+}
+
+func NewConditional(Condition Expr, ThenBranch Expr, ElseBranch Expr) *Conditional {
+	return &Conditional{Condition, ThenBranch, ElseBranch,}
+}
+
+func (obj *Conditional) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitConditional(obj)
+}
+
+type Lambda struct {
+	Keyword Token
+	Declaration Function
+}
+
+func NewLambda(Keyword Token, Declaration Function) *Lambda {
+	return &Lambda{Keyword, Declaration,}
+}
+
+func (obj *Lambda) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitLambda(obj)
+}
+
+type List struct {
+	Bracket Token
+	Elements []Expr
+}
+
+func NewList(Bracket Token, Elements []Expr) *List {
+	return &List{Bracket, Elements,}
+}
+
+func (obj *List) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitList(obj)
+}
+
+type OptionalChain struct {
+	Expression Expr
+}
+
+func NewOptionalChain(Expression Expr) *OptionalChain {
+	return &OptionalChain{Expression,}
+}
+
+func (obj *OptionalChain) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitOptionalChain(obj)
+}
+
+type SetSubscript struct {
+	Object Expr
+	Bracket Token
+	Index Expr
+	Value Expr
+}
+
+func NewSetSubscript(Object Expr, Bracket Token, Index Expr, Value Expr) *SetSubscript {
+	return &SetSubscript{Object, Bracket, Index, Value,}
+}
+
+func (obj *SetSubscript) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitSetSubscript(obj)
+}
+
+type This struct {
+	Keyword Token
+}
+
+func NewThis(Keyword Token) *This {
+	return &This{Keyword,}
+}
+
+func (obj *This) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitThis(obj)
+}
+
+type Assign struct {
+	Name Token
+	Value Expr
+}
+
+func NewAssign(Name Token, Value Expr) *Assign {
+	return &Assign{Name, Value,}
+}
+
+func (obj *Assign) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitAssign(obj)
+}
+
+type Get struct {
+	Object Expr
+	Name Token
+}
+
+func NewGet(Object Expr, Name Token) *Get {
+	return &Get{Object, Name,}
+}
+
+func (obj *Get) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitGet(obj)
+}
+
+type Optional struct {
+	Object Expr
+}
+
+func NewOptional(Object Expr) *Optional {
+	return &Optional{Object,}
+}
+
+func (obj *Optional) Accept(v ExprVisitor) (Object, error) {
+	return v.VisitOptional(obj)
+}
+
@@ -6,14 +6,24 @@ import (
 )
 
 type StmtVisitor interface {
-	VisitPrint(obj Print) (Object, error)
+	VisitStmtExpression(obj StmtExpression) (Object, error)
+	VisitIf(obj If) (Object, error)
+	VisitMatch(obj Match) (Object, error)
 	VisitReturn(obj Return) (Object, error)
+	VisitTry(obj Try) (Object, error)
+	VisitBreak(obj Break) (Object, error)
+	VisitVarPattern(obj VarPattern) (Object, error)
+	VisitFunction(obj Function) (Object, error)
+	VisitImport(obj Import) (Object, error)
+	VisitPrint(obj Print) (Object, error)
+	VisitBlock(obj Block) (Object, error)
+	VisitConst(obj Const) (Object, error)
+	VisitContinue(obj Continue) (Object, error)
+	VisitForIn(obj ForIn) (Object, error)
+	VisitThrow(obj Throw) (Object, error)
 	VisitVar(obj Var) (Object, error)
 	VisitWhile(obj While) (Object, error)
-	VisitBlock(obj Block) (Object, error)
-	VisitStmtExpression(obj StmtExpression) (Object, error)
-	VisitFunction(obj Function) (Object, error)
-	VisitIf(obj If) (Object, error)
+	VisitClass(obj Class) (Object, error)
 }
 
 type Stmt interface{
@@ -23,29 +33,31 @@ type Stmt interface{
 type Function struct {
 	Name Token
 	Params []Token
+	Defaults []Expr
+	Rest Token
 	Body []Stmt
 }
 
-func NewFunction(Name Token, Params []Token, Body []Stmt) Function {
-	return Function{Name, Params, Body,}
+func NewFunction(Name Token, Params []Token, Defaults []Expr, Rest Token, Body []Stmt) Function {
+	return Function{Name, Params, Defaults, Rest, Body,}
 }
 
 func (obj Function) Accept(v StmtVisitor) (Object, error) {
 	return v.VisitFunction(obj)
 }
 
-type If struct {
-	Condition Expr
-	ThenBranch Stmt
-	ElseBranch Stmt
+type Import struct {
+	Keyword Token
+	Path Token
+	Name Token
 }
 
-func NewIf(Condition Expr, ThenBranch Stmt, ElseBranch Stmt) If {
-	return If{Condition, ThenBranch, ElseBranch,}
+func NewImport(Keyword Token, Path Token, Name Token) Import {
+	return Import{Keyword, Path, Name,}
 }
 
-func (obj If) Accept(v StmtVisitor) (Object, error) {
-	return v.VisitIf(obj)
+func (obj Import) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitImport(obj)
 }
 
 type Print struct {
@@ -60,17 +72,69 @@ func (obj Print) Accept(v StmtVisitor) (Object, error) {
 	return v.VisitPrint(obj)
 }
 
-type Return struct {
+type Block struct {
+	Statements []Stmt
+}
+
+func NewBlock(Statements []Stmt) Block {
+	return Block{Statements,}
+}
+
+func (obj Block) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitBlock(obj)
+}
+
+type Const struct {
+	Name Token
+	Initializer Expr
+}
+
+func NewConst(Name Token, Initializer Expr) Const {
+	return Const{Name, Initializer,}
+}
+
+func (obj Const) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitConst(obj)
+}
+
+type Continue struct {
+	Keyword Token
+}
+
+func NewContinue(Keyword Token) Continue {
+	return Continue{Keyword,}
+}
+
+func (obj Continue) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitContinue(obj)
+}
+
+type ForIn struct {
+	Name Token
+	Keyword Token
+	Iterable Expr
+	Body Stmt
+}
+
+func NewForIn(Name Token, Keyword Token, Iterable Expr, Body Stmt) ForIn {
+	return ForIn{Name, Keyword, Iterable, Body,}
+}
+
+func (obj ForIn) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitForIn(obj)
+}
+
+type Throw struct {
 	Keyword Token
 	Value Expr
 }
 
-func NewReturn(Keyword Token, Value Expr) Return {
-	return Return{Keyword, Value,}
+func NewThrow(Keyword Token, Value Expr) Throw {
+	return Throw{Keyword, Value,}
 }
 
-func (obj Return) Accept(v StmtVisitor) (Object, error) {
-	return v.VisitReturn(obj)
+func (obj Throw) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitThrow(obj)
 }
 
 type Var struct {
@@ -89,26 +153,31 @@ func (obj Var) Accept(v StmtVisitor) (Object, error) {
 type While struct {
 	Condition Expr
 	Body Stmt
+	Increment Expr
 }
 
-func NewWhile(Condition Expr, Body Stmt) While {
-	return While{Condition, Body,}
+func NewWhile(Condition Expr, Body Stmt, Increment Expr) While {
+	return While{Condition, Body, Increment,}
 }
 
 func (obj While) Accept(v StmtVisitor) (Object, error) {
 	return v.VisitWhile(obj)
 }
 
-type Block struct {
-	Statements []Stmt
+type Class struct {
+	Name Token
+	Superclass Expr
+	Methods []Function
+	Getters []Function
+	StaticMethods []Function
 }
 
-func NewBlock(Statements []Stmt) Block {
-	return Block{Statements,}
+func NewClass(Name Token, Superclass Expr, Methods []Function, Getters []Function, StaticMethods []Function) Class {
+	return Class{Name, Superclass, Methods, Getters, StaticMethods,}
 }
 
-func (obj Block) Accept(v StmtVisitor) (Object, error) {
-	return v.VisitBlock(obj)
+func (obj Class) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitClass(obj)
 }
 
 type StmtExpression struct {
@@ -123,3 +192,90 @@ func (obj StmtExpression) Accept(v StmtVisitor) (Object, error) {
 	return v.VisitStmtExpression(obj)
 }
 
+type If struct {
+	Condition Expr
+	ThenBranch Stmt
+	ElseBranch Stmt
+}
+
+func NewIf(Condition Expr, ThenBranch Stmt, ElseBranch Stmt) If {
+	return If{Condition, ThenBranch, ElseBranch,}
+}
+
+func (obj If) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitIf(obj)
+}
+
+type Match struct {
+	Keyword Token
+	Subject Expr
+	Patterns [][]Expr
+	Guards []Expr
+	Bodies []Stmt
+	Default Stmt
+}
+
+func NewMatch(Keyword Token, Subject Expr, Patterns [][]Expr, Guards []Expr, Bodies []Stmt, Default Stmt) Match {
+	return Match{Keyword, Subject, Patterns, Guards, Bodies, Default,}
+}
+
+func (obj Match) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitMatch(obj)
+}
+
+type Return struct {
+	Keyword Token
+	Value Expr
+	TailCall bool
+}
+
+func NewReturn(Keyword Token, Value Expr, TailCall bool) Return {
+	return Return{Keyword, Value, TailCall,}
+}
+
+func (obj Return) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitReturn(obj)
+}
+
+type Try struct {
+	Body Stmt
+	CatchName Token
+	CatchBody Stmt
+	FinallyBody Stmt
+}
+
+func NewTry(Body Stmt, CatchName Token, CatchBody Stmt, FinallyBody Stmt) Try {
+	return Try{Body, CatchName, CatchBody, FinallyBody,}
+}
+
+func (obj Try) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitTry(obj)
+}
+
+type Break struct {
+	Keyword Token
+}
+
+func NewBreak(Keyword Token) Break {
+	return Break{Keyword,}
+}
+
+func (obj Break) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitBreak(obj)
+}
+
+type VarPattern struct {
+	Opening Token
+	Names []Token
+	Rest Token
+	Initializer Expr
+}
+
+func NewVarPattern(Opening Token, Names []Token, Rest Token, Initializer Expr) VarPattern {
+	return VarPattern{Opening, Names, Rest, Initializer,}
+}
+
+func (obj VarPattern) Accept(v StmtVisitor) (Object, error) {
+	return v.VisitVarPattern(obj)
+}
+
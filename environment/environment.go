@@ -9,6 +9,8 @@ import (
 type Environment struct {
     enclosing *Environment
     values map[string]Object
+    // names bound with "const", which can't be assigned after definition
+    constants map[string]bool
 }
 
 // Environment "constructor"
@@ -18,12 +20,23 @@ func NewEnvironment(params ...*Environment) *Environment {
     if len(params) > 0 {
         enclosing = params[0]
     }
-    return &Environment{enclosing: enclosing, values: make(map[string]Object)}
+    return &Environment{
+        enclosing: enclosing,
+        values: make(map[string]Object),
+        constants: make(map[string]bool),
+    }
 }
 
 // function to define a new variable with a value
 func (e *Environment) Define(name string, value Object) {
     e.values[name] = value
+    delete(e.constants, name)
+}
+
+// function to define a new variable that can't be reassigned
+func (e *Environment) DefineConst(name string, value Object) {
+    e.values[name] = value
+    e.constants[name] = true
 }
 
 // function to retrieve the value associated with a given variable name
@@ -44,8 +57,7 @@ func (e *Environment) Get(name Token) (Object, error) {
 // recursively check the enclosing scope for the variable if not found
 func (e *Environment) Assign(name Token, value Object) error {
     if _, ok := e.values[name.Lexeme]; ok {
-        e.values[name.Lexeme] = value
-        return nil
+        return e.set(name, value)
     }
 
     if e.enclosing != nil {
@@ -55,3 +67,59 @@ func (e *Environment) Assign(name Token, value Object) error {
     return &RuntimeError{name, "Undefined variable '" + name.Lexeme + "'"}
 }
 
+// function to retrieve a value from the scope exactly distance hops away
+// the resolver has already checked that the variable exists there
+func (e *Environment) GetAt(distance int, name string) Object {
+    return e.ancestor(distance).values[name]
+}
+
+// function to assign a value in the scope exactly distance hops away
+func (e *Environment) AssignAt(distance int, name Token, value Object) error {
+    return e.ancestor(distance).set(name, value)
+}
+
+// function to overwrite a variable defined directly in this scope
+// constants are only caught here when the resolver couldn't see them
+func (e *Environment) set(name Token, value Object) error {
+    if e.constants[name.Lexeme] {
+        return &RuntimeError{name, "Can't assign to constant '" + name.Lexeme + "'"}
+    }
+
+    e.values[name.Lexeme] = value
+    return nil
+}
+
+// function to check if a variable defined directly in this scope is a constant
+func (e *Environment) IsConst(name string) bool {
+    return e.constants[name]
+}
+
+// function to return the outermost scope, which holds the globals
+func (e *Environment) Root() *Environment {
+    env := e
+    for env.enclosing != nil {
+        env = env.enclosing
+    }
+
+    return env
+}
+
+// function to return a copy of the variables defined directly in this scope
+func (e *Environment) Values() map[string]Object {
+    values := make(map[string]Object, len(e.values))
+    for name, value := range e.values {
+        values[name] = value
+    }
+
+    return values
+}
+
+// function to walk up the enclosing chain a fixed number of hops
+func (e *Environment) ancestor(distance int) *Environment {
+    env := e
+    for k := 0; k < distance; k++ {
+        env = env.enclosing
+    }
+
+    return env
+}
@@ -4,8 +4,13 @@ import (
     . "glox/util"
 )
 
+// MaxArity value for callables that accept any number of extra arguments
+const VARIADIC = -1
+
 type Callable interface {
-    Arity() int
+    // fewest and most arguments the callable accepts
+    MinArity() int
+    MaxArity() int
     Call(interpreter Interpreter, args []Object) (Object, error)
     ToString() string
 }
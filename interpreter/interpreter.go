@@ -7,6 +7,8 @@ import (
     . "glox/environment"
     . "glox/loxError"
     "reflect"
+    "strconv"
+    "strings"
     "fmt"
     "errors"
 )
@@ -15,16 +17,26 @@ type Interpreter struct {
     ev ExprVisitor
     sv StmtVisitor
     env *Environment
-    globals *Environment
+    locals map[Expr]int
+    modules *modules
 }
 
 // Interpreter "constructor"
 func NewInterpreter() Interpreter {
     global := NewEnvironment()
-    var clock Clock 
-    global.Define("clock", clock)
+    defineNatives(global)
 
-    return Interpreter{env: global, globals: global}
+    return Interpreter{
+        env: global,
+        locals: make(map[Expr]int),
+        modules: newModules(),
+    }
+}
+
+// function for the resolver to record how many scopes away
+// the variable referenced by expr is defined
+func (i Interpreter) Resolve(expr Expr, depth int) {
+    i.locals[expr] = depth
 }
 
 // function to interpret a series of statements
@@ -32,20 +44,28 @@ func (i Interpreter) Interpret(statements []Stmt) {
     for _, statement := range statements {
         err := i.execute(statement)
         var re *RuntimeError
+        var te *ThrowError
         if errors.As(err, &re) {
             ErrorRuntime(*re)
             return
+        } else if errors.As(err, &te) {
+            msg, err := i.stringify(te.Value)
+            if err != nil {
+                msg = stringify(te.Value)
+            }
+            ErrorRuntime(RuntimeError{te.Keyword, "Uncaught exception: " + msg})
+            return
         }
     }
 }
 
 // VISTITOR FUNCTIONS
 
-func (i Interpreter) VisitLiteral(expr Literal) (Object, error) {
+func (i Interpreter) VisitLiteral(expr *Literal) (Object, error) {
     return expr.Value, nil
 }
 
-func (i Interpreter) VisitLogical(expr Logical) (Object, error) {
+func (i Interpreter) VisitLogical(expr *Logical) (Object, error) {
     left, err := i.evaluate(expr.Left)
     if err != nil { return nil, err }
 
@@ -55,15 +75,28 @@ func (i Interpreter) VisitLogical(expr Logical) (Object, error) {
     if expr.Operator.Type == OR && isTruthy(left) {
         return left, nil 
     }
+    if expr.Operator.Type == QUESTION_QUESTION && left != nil {
+        return left, nil
+    }
 
     return i.evaluate(expr.Right)
 }
 
-func (i Interpreter) VisitGrouping(expr Grouping) (Object, error) {
+func (i Interpreter) VisitConditional(expr *Conditional) (Object, error) {
+    cond, err := i.evaluate(expr.Condition)
+    if err != nil { return nil, err }
+
+    if isTruthy(cond) {
+        return i.evaluate(expr.ThenBranch)
+    }
+    return i.evaluate(expr.ElseBranch)
+}
+
+func (i Interpreter) VisitGrouping(expr *Grouping) (Object, error) {
     return i.evaluate(expr.Expression)
 }
 
-func (i Interpreter) VisitUnary(expr Unary) (Object, error) {
+func (i Interpreter) VisitUnary(expr *Unary) (Object, error) {
     right, err := i.evaluate(expr.Right)
     if err != nil { return nil, err }
 
@@ -71,122 +104,399 @@ func (i Interpreter) VisitUnary(expr Unary) (Object, error) {
     case BANG:
         return !isTruthy(right), nil
     case MINUS:
-        err = verifyType("float64", "number", expr.Operator, right)
+        err = verifyNumbers(expr.Operator, right)
         if err != nil { return nil, err }
 
-        return -(right.(float64)), nil
+        return negate(right), nil
     }
 
     return nil, nil
 }
 
-func (i Interpreter) VisitVariable(expr Variable) (Object, error) {
-    return i.env.Get(expr.Name)
+func (i Interpreter) VisitVariable(expr *Variable) (Object, error) {
+    return i.lookUpVariable(expr.Name, expr)
 }
 
-func (i Interpreter) VisitBinary(expr Binary) (Object, error) {
+// function to fetch a variable from the scope the resolver found it in
+// unresolved variables are assumed to be global to the running module,
+// which is always the outermost scope of the current environment
+func (i Interpreter) lookUpVariable(name Token, expr Expr) (Object, error) {
+    if distance, ok := i.locals[expr]; ok {
+        return i.env.GetAt(distance, name.Lexeme), nil
+    }
+
+    return i.env.Root().Get(name)
+}
+
+func (i Interpreter) VisitBinary(expr *Binary) (Object, error) {
     left, err := i.evaluate(expr.Left)
     if err != nil { return nil, err }
 
     right, err := i.evaluate(expr.Right)
     if err != nil { return nil, err }
 
-    switch expr.Operator.Type {
-    case GREAT:
-        err = verifyType("float64", "number", expr.Operator, right, left)
-        if err != nil { return nil, err }
+    return i.binaryOp(expr.Operator, left, right)
+}
 
-        return left.(float64) > right.(float64), nil
-        
-    case GREAT_EQUAL:
-        err = verifyType("float64", "number", expr.Operator, right, left)
-        if err != nil { return nil, err }
+// function to call x.__lt(y), found is false when x doesn't define it
+func (i Interpreter) lessThan(operator Token, x, y Object) (bool, bool, error) {
+    instance, ok := x.(*LoxInstance)
+    if !ok {
+        return false, false, nil
+    }
 
-        return left.(float64) >= right.(float64), nil
+    ret, found, err := i.callMethod(operator, instance, "__lt", y)
+    if !found || err != nil {
+        return false, found, err
+    }
+    return isTruthy(ret), true, nil
+}
 
-    case LESS:
-        err = verifyType("float64", "number", expr.Operator, right, left)
-        if err != nil {
-            return nil, err
-        }
-        return left.(float64) < right.(float64), nil
+// function to apply <, <=, > or >= using __lt from whichever operand has it
+// found is false when neither operand defines __lt
+func (i Interpreter) compare(operator Token, left, right Object) (bool, bool, error) {
+    // a < b and a >= b ask a.__lt(b), a > b and a <= b ask b.__lt(a)
+    first, second := left, right
+    if operator.Type == GREAT || operator.Type == LESS_EQUAL {
+        first, second = right, left
+    }
+    negate := operator.Type == GREAT_EQUAL || operator.Type == LESS_EQUAL
 
-    case LESS_EQUAL:
-        err = verifyType("float64", "number", expr.Operator, right, left)
-        if err != nil { return nil, err }
+    less, found, err := i.lessThan(operator, first, second)
+    if found {
+        return less != negate, true, err
+    }
 
-        return left.(float64) <= right.(float64), nil
+    // only the other operand orders itself, so ask the reverse question
+    // and use equality to tell the two strict cases apart
+    reversed, found, err := i.lessThan(operator, second, first)
+    if !found || err != nil {
+        return false, found, err
+    }
+
+    equal, err := i.isEqual(operator, left, right)
+    if err != nil { return false, true, err }
+
+    if negate {
+        return reversed || equal, true, nil
+    }
+    return !reversed && !equal, true, nil
+}
+
+// special method an instance on the left of an arithmetic operator can define
+var operatorMethods = map[TokenType]string{
+    PLUS: "__add",
+    MINUS: "__sub",
+    STAR: "__mul",
+    SLASH: "__div",
+    PERCENT: "__mod",
+    STAR_STAR: "__pow",
+    TILDE_SLASH: "__floordiv",
+}
+
+// function to apply a binary operator to two already evaluated operands
+func (i Interpreter) binaryOp(operator Token, left, right Object) (Object, error) {
+    if instance, ok := left.(*LoxInstance); ok {
+        if name, ok := operatorMethods[operator.Type]; ok {
+            ret, found, err := i.callMethod(operator, instance, name, right)
+            if found {
+                return ret, err
+            }
+        }
+    }
 
+    switch operator.Type {
     case BANG_EQUAL:
-        return !isEqual(left, right), nil
+        equal, err := i.isEqual(operator, left, right)
+        return !equal, err
 
     case EQUAL_EQUAL:
-        return isEqual(left, right), nil
-
-    case MINUS:
-        err = verifyType("float64", "number", expr.Operator, right, left)
-        if err != nil { return nil, err }
+        return i.isEqual(operator, left, right)
 
-        return left.(float64) - right.(float64), nil
+    case LESS, LESS_EQUAL, GREAT, GREAT_EQUAL:
+        if ret, found, err := i.compare(operator, left, right); found {
+            return ret, err
+        }
 
     case PLUS:
-        if typeOf(left) == "float64" && typeOf(right) == "float64" {
-            return left.(float64) + right.(float64), nil
+        if isString(left) || isString(right) {
+            if i.isPrintable(left) && i.isPrintable(right) {
+                l, err := i.stringify(left)
+                if err != nil { return nil, err }
+                r, err := i.stringify(right)
+                if err != nil { return nil, err }
+                return l + r, nil
+            }
+            return nil, &RuntimeError{operator, "Operand(s) must be two numbers or two strings"}
         }
+    }
 
-        if typeOf(left) == "string" || typeOf(right) == "string" {
-            if typeOf(left) == "float64" {
-                left = fmt.Sprintf("%v", left.(float64))
-            }
-            if typeOf(right) == "float64" {
-                right = fmt.Sprintf("%v", right.(float64))
-            }
-            return left.(string) + right.(string), nil
+    err := verifyNumbers(operator, right, left)
+    if err != nil { return nil, err }
+
+    // integers only stay integers when both sides are, otherwise promote
+    if l, ok := left.(int64); ok {
+        if r, ok := right.(int64); ok {
+            return intBinary(operator, l, r)
         }
+    }
 
-        return nil, &RuntimeError{expr.Operator, "Operand(s) must be two numbers or two strings"}
+    return floatBinary(operator, toFloat(left), toFloat(right))
+}
 
-    case SLASH:
-        err = verifyType("float64", "number", expr.Operator, right, left)
-        if err != nil { return nil, err }
+func (i Interpreter) VisitCall(expr *Call) (Object, error) {
+    callee, args, err := i.evaluateCall(expr)
+    if err != nil { return nil, err }
+
+    return i.call(expr.Paren, callee, args)
+}
+
+// function to evaluate the callee and then the arguments of a call
+func (i Interpreter) evaluateCall(expr *Call) (Object, []Object, error) {
+    callee, err := i.evaluate(expr.Callee)
+    if err != nil { return nil, nil, err }
+
+    args := make([]Object, 0)
+    for _, arg := range expr.Arguments {
+        val, err := i.evaluate(arg)
+        if err != nil { return nil, nil, err }
+        args = append(args, val)
+    }
+
+    return callee, args, nil
+}
+
+// function to call an already evaluated callee with its arguments
+func (i Interpreter) call(paren Token, callee Object, args []Object) (Object, error) {
+    function, err := checkCall(paren, callee, args)
+    if err != nil { return nil, err }
+
+    ret, err := function.Call(i, args)
+    var ne *NativeError
+    if errors.As(err, &ne) {
+        return nil, &RuntimeError{paren, ne.Msg}
+    }
+
+    return ret, err
+}
+
+// function to check a value is callable with the given number of arguments
+func checkCall(paren Token, callee Object, args []Object) (Callable, error) {
+    function, ok := callee.(Callable)
+    if !ok {
+        return nil, &RuntimeError{paren, "Can only call functions and classes"}
+    }
+
+    min, max := function.MinArity(), function.MaxArity()
+    if len(args) < min || (max != VARIADIC && len(args) > max) {
+        expected := fmt.Sprintf("%v", min)
+        if max == VARIADIC {
+            expected = fmt.Sprintf("at least %v", min)
+        } else if min != max {
+            expected = fmt.Sprintf("%v to %v", min, max)
+        }
 
-        if right.(float64) == 0 {
-            return nil, &RuntimeError{expr.Operator, "Cannot divide by zero"}
+        errMsg := fmt.Sprintf("Expected %v arguments but got %v", expected, len(args))
+        return nil, &RuntimeError{paren, errMsg}
+    }
+
+    return function, nil
+}
+
+func (i Interpreter) VisitGet(expr *Get) (Object, error) {
+    object, err := i.evaluate(expr.Object)
+    if err != nil { return nil, err }
+
+    switch val := object.(type) {
+    case *LoxInstance:
+        return i.getProperty(val, expr.Name)
+    case *LoxClass:
+        return val.Get(expr.Name)
+    case *LoxModule:
+        return val.Get(expr.Name)
+    }
+
+    return nil, &RuntimeError{expr.Name, "Only instances, classes and modules have properties"}
+}
+
+// function to read a property of an instance
+// fields shadow getters, which are run on access, which shadow methods
+func (i Interpreter) getProperty(instance *LoxInstance, name Token) (Object, error) {
+    if _, ok := instance.fields[name.Lexeme]; !ok {
+        if getter := instance.class.FindGetter(name.Lexeme); getter != nil {
+            return getter.Bind(instance).Call(i, nil)
         }
+    }
+
+    return instance.Get(name)
+}
 
-        return left.(float64) / right.(float64), nil
+func (i Interpreter) VisitOptional(expr *Optional) (Object, error) {
+    object, err := i.evaluate(expr.Object)
+    if err != nil { return nil, err }
+
+    if object == nil {
+        return nil, &NilChainError{}
+    }
+    return object, nil
+}
+
+func (i Interpreter) VisitOptionalChain(expr *OptionalChain) (Object, error) {
+    val, err := i.evaluate(expr.Expression)
+    var nce *NilChainError
+    if errors.As(err, &nce) {
+        return nil, nil
+    }
+
+    return val, err
+}
+
+func (i Interpreter) VisitSet(expr *Set) (Object, error) {
+    object, err := i.evaluate(expr.Object)
+    if err != nil { return nil, err }
+
+    instance, ok := object.(*LoxInstance)
+    if !ok {
+        return nil, &RuntimeError{expr.Name, "Only instances have fields"}
+    }
 
-    case STAR:
-        err = verifyType("float64", "number", expr.Operator, right, left)
+    value, err := i.evaluate(expr.Value)
+    if err != nil { return nil, err }
+
+    instance.Set(expr.Name, value)
+    return value, nil
+}
+
+func (i Interpreter) VisitInterpolation(expr *Interpolation) (Object, error) {
+    var ret strings.Builder
+    for _, part := range expr.Parts {
+        val, err := i.evaluate(part)
+        if err != nil { return nil, err }
+        str, err := i.stringify(val)
         if err != nil { return nil, err }
+        ret.WriteString(str)
+    }
+
+    return ret.String(), nil
+}
 
-        return left.(float64) * right.(float64), nil
+func (i Interpreter) VisitList(expr *List) (Object, error) {
+    elements := make([]Object, 0, len(expr.Elements))
+    for _, element := range expr.Elements {
+        val, err := i.evaluate(element)
+        if err != nil { return nil, err }
+        elements = append(elements, val)
     }
 
-    return nil, nil
+    return NewLoxList(elements), nil
 }
 
-func (i Interpreter) VisitCall(expr Call) (Object, error) {
-    callee, err := i.evaluate(expr.Callee)
+func (i Interpreter) VisitMap(expr *Map) (Object, error) {
+    m := NewLoxMap()
+    for k := range expr.Keys {
+        key, err := i.evaluate(expr.Keys[k])
+        if err != nil { return nil, err }
+
+        value, err := i.evaluate(expr.Values[k])
+        if err != nil { return nil, err }
+
+        err = m.Set(expr.Brace, key, value)
+        if err != nil { return nil, err }
+    }
+
+    return m, nil
+}
+
+func (i Interpreter) VisitSubscript(expr *Subscript) (Object, error) {
+    object, err := i.evaluate(expr.Object)
     if err != nil { return nil, err }
 
-    args := make([]Object, 0)
-    for _, arg := range expr.Arguments {
-        val, err := i.evaluate(arg)
+    index, err := i.evaluate(expr.Index)
+    if err != nil { return nil, err }
+
+    return i.getSubscript(expr.Bracket, object, index)
+}
+
+func (i Interpreter) VisitSetSubscript(expr *SetSubscript) (Object, error) {
+    object, err := i.evaluate(expr.Object)
+    if err != nil { return nil, err }
+
+    index, err := i.evaluate(expr.Index)
+    if err != nil { return nil, err }
+
+    value, err := i.evaluate(expr.Value)
+    if err != nil { return nil, err }
+
+    err = setSubscript(expr.Bracket, object, index, value)
+    if err != nil { return nil, err }
+
+    return value, nil
+}
+
+// function to read object[index] for an already evaluated object and index
+// strings are indexed by code point and give back a one character string
+// instances can be indexed by defining __index
+func (i Interpreter) getSubscript(bracket Token, object, index Object) (Object, error) {
+    switch val := object.(type) {
+    case *LoxInstance:
+        ret, found, err := i.callMethod(bracket, val, "__index", index)
+        if found {
+            return ret, err
+        }
+    case *LoxList:
+        return val.Get(bracket, index)
+    case *LoxMap:
+        return val.Get(bracket, index)
+    case string:
+        chars := []rune(val)
+        k, err := checkIndex(bracket, index, len(chars))
         if err != nil { return nil, err }
-        args = append(args, val)
+
+        return string(chars[k]), nil
     }
 
-    function, ok := callee.(Callable)
+    return nil, &RuntimeError{bracket, "Only lists, maps, strings and instances with __index can be indexed"}
+}
+
+// function to write object[index] for an already evaluated object and index
+func setSubscript(bracket Token, object, index, value Object) error {
+    switch val := object.(type) {
+    case *LoxList:
+        return val.Set(bracket, index, value)
+    case *LoxMap:
+        return val.Set(bracket, index, value)
+    }
+
+    if _, ok := object.(string); ok {
+        return &RuntimeError{bracket, "Strings are immutable"}
+    }
+    return &RuntimeError{bracket, "Only lists and maps can be indexed"}
+}
+
+func (i Interpreter) VisitThis(expr *This) (Object, error) {
+    return i.lookUpVariable(expr.Keyword, expr)
+}
+
+func (i Interpreter) VisitSuper(expr *Super) (Object, error) {
+    distance, ok := i.locals[expr]
     if !ok {
-        return nil, &RuntimeError{expr.Paren, "Can only call functions and classes"}
+        return nil, &RuntimeError{expr.Keyword, "Can't use 'super' in a class with no superclass"}
     }
-    if len(args) != function.Arity() {
-        errMsg := fmt.Sprintf("Expected %v but got %v", function.Arity(), len(args))
-        return nil, &RuntimeError{expr.Paren, errMsg}
+    superclass := i.env.GetAt(distance, "super").(*LoxClass)
+
+    // "this" is always bound in the scope just inside the one holding "super"
+    object := i.env.GetAt(distance - 1, "this")
+
+    if getter := superclass.FindGetter(expr.Method.Lexeme); getter != nil {
+        return getter.Bind(object.(*LoxInstance)).Call(i, nil)
     }
 
-    return function.Call(i, args)
+    method := superclass.FindMethod(expr.Method.Lexeme)
+    if method == nil {
+        return nil, &RuntimeError{expr.Method, "Undefined property '" + expr.Method.Lexeme + "'"}
+    }
+
+    return method.Bind(object.(*LoxInstance)), nil
 }
 
 func (i Interpreter) evaluate(expr Expr) (Object, error) {
@@ -226,11 +536,79 @@ func (i Interpreter) VisitStmtExpression(stmt StmtExpression) (Object, error) {
 }
 
 func (i Interpreter) VisitFunction(stmt Function) (Object, error) {
-    function := NewLoxFunction(stmt, i.env)
+    function := NewLoxFunction(stmt, i.env, false)
     i.env.Define(stmt.Name.Lexeme, function)
     return nil, nil
 }
 
+func (i Interpreter) VisitLambda(expr *Lambda) (Object, error) {
+    return NewLoxFunction(expr.Declaration, i.env, false), nil
+}
+
+func (i Interpreter) VisitClass(stmt Class) (Object, error) {
+    var superclass *LoxClass = nil
+    if stmt.Superclass != nil {
+        val, err := i.evaluate(stmt.Superclass)
+        if err != nil { return nil, err }
+
+        class, ok := val.(*LoxClass)
+        if !ok {
+            return nil, &RuntimeError{stmt.Superclass.(*Variable).Name, "Superclass must be a class"}
+        }
+        superclass = class
+    }
+
+    i.env.Define(stmt.Name.Lexeme, nil)
+
+    enclosing := i.env
+    if superclass != nil {
+        i.env = NewEnvironment(i.env)
+        i.env.Define("super", superclass)
+    }
+
+    methods := make(map[string]*LoxFunction)
+    for _, method := range stmt.Methods {
+        function := NewLoxFunction(method, i.env, method.Name.Lexeme == "init")
+        methods[method.Name.Lexeme] = function
+    }
+
+    getters := make(map[string]*LoxFunction)
+    for _, getter := range stmt.Getters {
+        getters[getter.Name.Lexeme] = NewLoxFunction(getter, i.env, false)
+    }
+
+    // static methods close over the scope outside "super" like the resolver expects
+    statics := make(map[string]*LoxFunction)
+    for _, method := range stmt.StaticMethods {
+        statics[method.Name.Lexeme] = NewLoxFunction(method, enclosing, false)
+    }
+
+    class := NewLoxClass(stmt.Name.Lexeme, superclass, methods, getters, statics)
+    i.env = enclosing
+    err := i.env.Assign(stmt.Name, class)
+    return nil, err
+}
+
+func (i Interpreter) VisitImport(stmt Import) (Object, error) {
+    module, err := i.importModule(stmt.Keyword, stmt.Path.Literal.(string))
+    if err != nil { return nil, err }
+
+    if stmt.Name.Lexeme != "" {
+        i.env.Define(stmt.Name.Lexeme, module)
+        return nil, nil
+    }
+
+    // constants stay constant in the importing module
+    for name, value := range module.Definitions() {
+        if module.env.IsConst(name) {
+            i.env.DefineConst(name, value)
+        } else {
+            i.env.Define(name, value)
+        }
+    }
+    return nil, nil
+}
+
 func (i Interpreter) VisitIf(stmt If) (Object, error) {
     cond, err := i.evaluate(stmt.Condition)
     if err != nil { return nil, err }
@@ -246,16 +624,61 @@ func (i Interpreter) VisitIf(stmt If) (Object, error) {
     return nil, nil
 }
 
+func (i Interpreter) VisitMatch(stmt Match) (Object, error) {
+    subject, err := i.evaluate(stmt.Subject)
+    if err != nil { return nil, err }
+
+    // the first case with a matching pattern and a truthy guard wins
+    for k := range stmt.Bodies {
+        matched := false
+        for _, pattern := range stmt.Patterns[k] {
+            val, err := i.evaluate(pattern)
+            if err != nil { return nil, err }
+
+            equal, err := i.isEqual(stmt.Keyword, subject, val)
+            if err != nil { return nil, err }
+            if equal {
+                matched = true
+                break
+            }
+        }
+        if !matched {
+            continue
+        }
+
+        if stmt.Guards[k] != nil {
+            guard, err := i.evaluate(stmt.Guards[k])
+            if err != nil { return nil, err }
+            if !isTruthy(guard) {
+                continue
+            }
+        }
+
+        return nil, i.execute(stmt.Bodies[k])
+    }
+
+    if stmt.Default != nil {
+        return nil, i.execute(stmt.Default)
+    }
+    return nil, nil
+}
+
 func (i Interpreter) VisitPrint(stmt Print) (Object, error) {
     val, err := i.evaluate(stmt.Expression)
-    if err == nil {
-        fmt.Println(stringify(val))
-    }
+    if err != nil { return nil, err }
 
-    return nil, err
+    str, err := i.stringify(val)
+    if err != nil { return nil, err }
+
+    fmt.Println(str)
+    return nil, nil
 }
 
 func (i Interpreter) VisitReturn(stmt Return) (Object, error) {
+    if stmt.TailCall {
+        return i.tailCall(stmt.Value.(*Call))
+    }
+
     var val Object = nil
     if stmt.Value != nil {
         value, err := i.evaluate(stmt.Value) 
@@ -268,6 +691,49 @@ func (i Interpreter) VisitReturn(stmt Return) (Object, error) {
     return nil, &ReturnError{ val }
 }
 
+func (i Interpreter) VisitThrow(stmt Throw) (Object, error) {
+    val, err := i.evaluate(stmt.Value)
+    if err != nil { return nil, err }
+
+    return nil, &ThrowError{stmt.Keyword, val}
+}
+
+// class of the error objects handed to catch blocks for runtime errors
+var runtimeErrorClass = NewLoxClass("RuntimeError", nil,
+    make(map[string]*LoxFunction), make(map[string]*LoxFunction), make(map[string]*LoxFunction))
+
+func (i Interpreter) VisitTry(stmt Try) (Object, error) {
+    err := i.execute(stmt.Body)
+
+    if stmt.CatchBody != nil {
+        var caught Object
+        var te *ThrowError
+        var re *RuntimeError
+        if errors.As(err, &te) {
+            caught = te.Value
+        } else if errors.As(err, &re) {
+            instance := NewLoxInstance(runtimeErrorClass)
+            instance.fields["message"] = re.Msg
+            instance.fields["line"] = int64(re.Token.Line)
+            caught = instance
+        }
+
+        if te != nil || re != nil {
+            env := NewEnvironment(i.env)
+            env.Define(stmt.CatchName.Lexeme, caught)
+            err = i.executeBlock(stmt.CatchBody.(Block).Statements, env)
+        }
+    }
+
+    // finally always runs, an error raised inside it replaces the pending one
+    if stmt.FinallyBody != nil {
+        finallyErr := i.execute(stmt.FinallyBody)
+        if finallyErr != nil { return nil, finallyErr }
+    }
+
+    return nil, err
+}
+
 func (i Interpreter) VisitVar(stmt Var) (Object, error) {
     var val Object = nil
     if stmt.Initializer != nil {
@@ -282,13 +748,48 @@ func (i Interpreter) VisitVar(stmt Var) (Object, error) {
     return nil, nil
 }
 
+func (i Interpreter) VisitConst(stmt Const) (Object, error) {
+    val, err := i.evaluate(stmt.Initializer)
+    if err != nil { return nil, err }
+
+    i.env.DefineConst(stmt.Name.Lexeme, val)
+    return nil, nil
+}
+
+func (i Interpreter) VisitVarPattern(stmt VarPattern) (Object, error) {
+    val, err := i.evaluate(stmt.Initializer)
+    if err != nil { return nil, err }
+
+    values, rest, err := destructure(stmt.Opening, stmt.Names, val)
+    if err != nil { return nil, err }
+
+    for k, name := range stmt.Names {
+        i.env.Define(name.Lexeme, values[k])
+    }
+    if stmt.Rest.Lexeme != "" {
+        i.env.Define(stmt.Rest.Lexeme, rest)
+    }
+    return nil, nil
+}
+
 func (i Interpreter) VisitWhile(stmt While) (Object, error) {
     cond, err := i.evaluate(stmt.Condition)
     if err != nil { return nil, err }
 
     for isTruthy(cond) {
         err = i.execute(stmt.Body)
-        if err != nil { return nil, err }
+        var be *BreakError
+        var ce *ContinueError
+        if errors.As(err, &be) {
+            break
+        } else if err != nil && !errors.As(err, &ce) {
+            return nil, err
+        }
+
+        if stmt.Increment != nil {
+            _, err = i.evaluate(stmt.Increment)
+            if err != nil { return nil, err }
+        }
 
         cond, err = i.evaluate(stmt.Condition)
         if err != nil { return nil, err }
@@ -297,14 +798,298 @@ func (i Interpreter) VisitWhile(stmt While) (Object, error) {
     return nil, nil
 }
 
-func (i Interpreter) VisitAssign(expr Assign) (Object, error) {
+func (i Interpreter) VisitForIn(stmt ForIn) (Object, error) {
+    iterable, err := i.evaluate(stmt.Iterable)
+    if err != nil { return nil, err }
+
+    next, err := i.iterate(stmt.Keyword, iterable)
+    if err != nil { return nil, err }
+
+    for {
+        val, ok, err := next()
+        if err != nil { return nil, err }
+        if !ok {
+            break
+        }
+
+        // a new scope per iteration so closures capture that iteration's value
+        env := NewEnvironment(i.env)
+        env.Define(stmt.Name.Lexeme, val)
+        err = i.executeBlock([]Stmt{stmt.Body}, env)
+        var be *BreakError
+        var ce *ContinueError
+        if errors.As(err, &be) {
+            break
+        } else if err != nil && !errors.As(err, &ce) {
+            return nil, err
+        }
+    }
+
+    return nil, nil
+}
+
+// function returning the next value of an iteration, ok is false once it's done
+type iterator func() (val Object, ok bool, err error)
+
+// function to create an iterator over a list, map, string, range or an
+// instance implementing iterator(), whose result's next() returns nil when done
+func (i Interpreter) iterate(keyword Token, iterable Object) (iterator, error) {
+    switch val := iterable.(type) {
+    case *LoxList:
+        // the length is checked every step so appends during the loop are seen
+        k := 0
+        return func() (Object, bool, error) {
+            if k >= len(val.Elements) {
+                return nil, false, nil
+            }
+            k++
+            return val.Elements[k - 1], true, nil
+        }, nil
+    case *LoxMap:
+        keys := val.Keys()
+        k := 0
+        return func() (Object, bool, error) {
+            if k >= len(keys) {
+                return nil, false, nil
+            }
+            k++
+            return keys[k - 1], true, nil
+        }, nil
+    case string:
+        chars := []rune(val)
+        k := 0
+        return func() (Object, bool, error) {
+            if k >= len(chars) {
+                return nil, false, nil
+            }
+            k++
+            return string(chars[k - 1]), true, nil
+        }, nil
+    case *LoxRange:
+        current, done := val.start, false
+        return func() (Object, bool, error) {
+            if done || !val.contains(current) {
+                return nil, false, nil
+            }
+            ret := current
+            // a step that would overflow past the end of int64 ends the range
+            next, ok := val.next(current)
+            current, done = next, !ok
+            return ret, true, nil
+        }, nil
+    case *LoxInstance:
+        it, ok, err := i.callMethod(keyword, val, "iterator")
+        if err != nil { return nil, err }
+        if !ok {
+            return nil, &RuntimeError{keyword, "Instance of '" + val.class.Name + "' has no iterator() method"}
+        }
+
+        itInstance, ok := it.(*LoxInstance)
+        if !ok || itInstance.class.FindMethod("next") == nil {
+            return nil, &RuntimeError{keyword, "iterator() must return an instance with a next() method"}
+        }
+
+        return func() (Object, bool, error) {
+            next, _, err := i.callMethod(keyword, itInstance, "next")
+            if err != nil { return nil, false, err }
+            return next, next != nil, nil
+        }, nil
+    }
+
+    return nil, &RuntimeError{keyword, "Can only iterate over lists, maps, strings, ranges and iterable instances"}
+}
+
+// function to call a method of an instance's class with the given arguments
+// ok is false when the class doesn't define the method
+func (i Interpreter) callMethod(token Token, instance *LoxInstance, name string, args ...Object) (Object, bool, error) {
+    method := instance.class.FindMethod(name)
+    if method == nil {
+        return nil, false, nil
+    }
+
+    bound := method.Bind(instance)
+    if len(args) < bound.MinArity() || (bound.MaxArity() != VARIADIC && len(args) > bound.MaxArity()) {
+        errMsg := fmt.Sprintf("Method '%v' must accept %v arguments", name, len(args))
+        return nil, true, &RuntimeError{token, errMsg}
+    }
+
+    ret, err := bound.Call(i, args)
+    return ret, true, err
+}
+
+// function to hand a call to a Lox function back to the function returning
+// it rather than nesting it, natives and classes are simply called
+func (i Interpreter) tailCall(expr *Call) (Object, error) {
+    callee, args, err := i.evaluateCall(expr)
+    if err != nil { return nil, err }
+
+    if _, ok := callee.(*LoxFunction); !ok {
+        val, err := i.call(expr.Paren, callee, args)
+        if err != nil { return nil, err }
+        return nil, &ReturnError{ val }
+    }
+
+    _, err = checkCall(expr.Paren, callee, args)
+    if err != nil { return nil, err }
+    return nil, &TailCallError{expr.Paren, callee, args}
+}
+
+func (i Interpreter) VisitBreak(stmt Break) (Object, error) {
+    return nil, &BreakError{stmt.Keyword}
+}
+
+func (i Interpreter) VisitContinue(stmt Continue) (Object, error) {
+    return nil, &ContinueError{stmt.Keyword}
+}
+
+func (i Interpreter) VisitAssign(expr *Assign) (Object, error) {
+    value, err := i.evaluate(expr.Value)
+    if err != nil { return nil, err }
+
+    err = i.assignVariable(expr.Name, expr, value)
+    if err != nil { return nil, err }
+    return value, nil
+}
+
+func (i Interpreter) VisitAssignPattern(expr *AssignPattern) (Object, error) {
     value, err := i.evaluate(expr.Value)
     if err != nil { return nil, err }
 
-    i.env.Assign(expr.Name, value)
+    names := make([]Token, len(expr.Targets))
+    for k, target := range expr.Targets {
+        names[k] = target.(*Variable).Name
+    }
+
+    values, rest, err := destructure(expr.Opening, names, value)
+    if err != nil { return nil, err }
+
+    for k, target := range expr.Targets {
+        err = i.assignVariable(names[k], target, values[k])
+        if err != nil { return nil, err }
+    }
+    if expr.Rest != nil {
+        err = i.assignVariable(expr.Rest.(*Variable).Name, expr.Rest, rest)
+        if err != nil { return nil, err }
+    }
+    return value, nil
+}
+
+// function to pull the values a pattern binds out of a list or map
+// missing elements or keys give nil, rest holds a list of any extra elements
+func destructure(opening Token, names []Token, value Object) ([]Object, Object, error) {
+    values := make([]Object, len(names))
+    if opening.Type == LEFT_BRACE {
+        m, ok := value.(*LoxMap)
+        if !ok {
+            return nil, nil, &RuntimeError{opening, "Can only destructure a map with '{...}'"}
+        }
+
+        for k, name := range names {
+            if m.Has(name.Lexeme) {
+                values[k], _ = m.Get(name, name.Lexeme)
+            }
+        }
+        return values, nil, nil
+    }
+
+    list, ok := value.(*LoxList)
+    if !ok {
+        return nil, nil, &RuntimeError{opening, "Can only destructure a list with '[...]'"}
+    }
+
+    copy(values, list.Elements)
+    rest := make([]Object, 0)
+    if len(list.Elements) > len(names) {
+        rest = append(rest, list.Elements[len(names):]...)
+    }
+    return values, NewLoxList(rest), nil
+}
+
+// function to assign to a variable in the scope the resolver found it in
+// unresolved variables are assumed to be global to the running module
+func (i Interpreter) assignVariable(name Token, expr Expr, value Object) error {
+    if distance, ok := i.locals[expr]; ok {
+        return i.env.AssignAt(distance, name, value)
+    }
+
+    return i.env.Root().Assign(name, value)
+}
+
+// binary operator applied by each compound assignment or increment operator
+var compoundOperators = map[TokenType]TokenType{
+    PLUS_EQUAL: PLUS,
+    MINUS_EQUAL: MINUS,
+    STAR_EQUAL: STAR,
+    SLASH_EQUAL: SLASH,
+    PLUS_PLUS: PLUS,
+    MINUS_MINUS: MINUS,
+}
+
+func (i Interpreter) VisitUpdate(expr *Update) (Object, error) {
+    operator := expr.Operator
+    operator.Type = compoundOperators[expr.Operator.Type]
+
+    // each target's sub-expressions are evaluated exactly once
+    var object, index, old, value Object
+    var err error
+    switch target := expr.Target.(type) {
+    case *Variable:
+        old, err = i.lookUpVariable(target.Name, target)
+        if err != nil { return nil, err }
+
+        value, err = i.updatedValue(operator, old, expr.Value)
+        if err != nil { return nil, err }
+
+        err = i.assignVariable(target.Name, target, value)
+
+    case *Get:
+        object, err = i.evaluate(target.Object)
+        if err != nil { return nil, err }
+
+        instance, ok := object.(*LoxInstance)
+        if !ok {
+            return nil, &RuntimeError{target.Name, "Only instances have fields"}
+        }
+
+        old, err = i.getProperty(instance, target.Name)
+        if err != nil { return nil, err }
+
+        value, err = i.updatedValue(operator, old, expr.Value)
+        if err != nil { return nil, err }
+
+        instance.Set(target.Name, value)
+
+    case *Subscript:
+        object, err = i.evaluate(target.Object)
+        if err != nil { return nil, err }
+
+        index, err = i.evaluate(target.Index)
+        if err != nil { return nil, err }
+
+        old, err = i.getSubscript(target.Bracket, object, index)
+        if err != nil { return nil, err }
+
+        value, err = i.updatedValue(operator, old, expr.Value)
+        if err != nil { return nil, err }
+
+        err = setSubscript(target.Bracket, object, index, value)
+    }
+    if err != nil { return nil, err }
+
+    if expr.Postfix {
+        return old, nil
+    }
     return value, nil
 }
 
+// function to combine a target's current value with the right hand side
+func (i Interpreter) updatedValue(operator Token, old Object, rhs Expr) (Object, error) {
+    right, err := i.evaluate(rhs)
+    if err != nil { return nil, err }
+
+    return i.binaryOp(operator, old, right)
+}
+
 
 // function to return whether an Object is a truth-like value
 func isTruthy(obj Object) bool {
@@ -320,6 +1105,75 @@ func isTruthy(obj Object) bool {
     }
 }
 
+// function to compare two values with ==, deferring to the left one's __eq
+// or, when the left one has none, to the right one's
+func (i Interpreter) isEqual(operator Token, x, y Object) (bool, error) {
+    if instance, ok := x.(*LoxInstance); ok {
+        ret, found, err := i.callMethod(operator, instance, "__eq", y)
+        if found {
+            return isTruthy(ret), err
+        }
+    }
+    if instance, ok := y.(*LoxInstance); ok {
+        ret, found, err := i.callMethod(operator, instance, "__eq", x)
+        if found {
+            return isTruthy(ret), err
+        }
+    }
+
+    // collections compare element by element so that == and __eq apply inside them
+    switch l := x.(type) {
+    case *LoxList:
+        if r, ok := y.(*LoxList); ok {
+            return i.listsEqual(operator, l, r)
+        }
+    case *LoxMap:
+        if r, ok := y.(*LoxMap); ok {
+            return i.mapsEqual(operator, l, r)
+        }
+    }
+
+    return isEqual(x, y), nil
+}
+
+// function to compare two lists element by element
+func (i Interpreter) listsEqual(operator Token, x, y *LoxList) (bool, error) {
+    if x == y {
+        return true, nil
+    }
+    if len(x.Elements) != len(y.Elements) {
+        return false, nil
+    }
+
+    for k := range x.Elements {
+        equal, err := i.isEqual(operator, x.Elements[k], y.Elements[k])
+        if err != nil || !equal { return false, err }
+    }
+
+    return true, nil
+}
+
+// function to compare two maps key by key, ignoring insertion order
+func (i Interpreter) mapsEqual(operator Token, x, y *LoxMap) (bool, error) {
+    if x == y {
+        return true, nil
+    }
+    if x.Len() != y.Len() {
+        return false, nil
+    }
+
+    for _, key := range x.keys {
+        other, ok := y.values[key]
+        if !ok {
+            return false, nil
+        }
+        equal, err := i.isEqual(operator, x.values[key], other)
+        if err != nil || !equal { return false, err }
+    }
+
+    return true, nil
+}
+
 // function to return whether two objects are equal
 func isEqual(x, y Object) bool {
     if x == nil && y == nil {
@@ -330,6 +1184,23 @@ func isEqual(x, y Object) bool {
         return false
     }
 
+    // 1 == 1.0 so numbers compare by value whatever their type
+    if isNumber(x) && isNumber(y) {
+        if l, ok := x.(int64); ok {
+            if r, ok := y.(int64); ok {
+                return l == r
+            }
+        }
+        return toFloat(x) == toFloat(y)
+    }
+
+    // instances and collections compare by identity rather than by their fields,
+    // the interpreter's isEqual is what looks inside lists and maps
+    switch x.(type) {
+    case *LoxInstance, *LoxList, *LoxMap:
+        return x == y
+    }
+
     return reflect.DeepEqual(x, y)
 }
 
@@ -343,22 +1214,77 @@ func stringify(obj Object) string {
     if function, ok := obj.(Callable); ok {
         return function.ToString()
     }
+    if instance, ok := obj.(*LoxInstance); ok {
+        return instance.ToString()
+    }
+    if list, ok := obj.(*LoxList); ok {
+        return list.ToString()
+    }
+    if m, ok := obj.(*LoxMap); ok {
+        return m.ToString()
+    }
+    if module, ok := obj.(*LoxModule); ok {
+        return module.ToString()
+    }
+    if r, ok := obj.(*LoxRange); ok {
+        return r.ToString()
+    }
+    if num, ok := obj.(int64); ok {
+        return strconv.FormatInt(num, 10)
+    }
+    if num, ok := obj.(float64); ok {
+        return formatFloat(num)
+    }
 
     return fmt.Sprintf("%v", obj) 
 }
 
-// function to return the type of an Object
-func typeOf(obj Object) string {
-    return reflect.TypeOf(obj).String()
-}
+// function to convert a value to the string print shows, using __str for
+// instances that define it, including ones nested in lists and maps
+func (i Interpreter) stringify(obj Object) (string, error) {
+    switch val := obj.(type) {
+    case *LoxInstance:
+        method := val.class.FindMethod("__str")
+        if method == nil {
+            break
+        }
+
+        ret, _, err := i.callMethod(method.declaration.Name, val, "__str")
+        if err != nil { return "", err }
 
-// function to verify that the type of all objects are the passed in type
-func verifyType(match, eMsg string, operator Token, operands ...Object) error {
-    for _, operand := range operands {
-        if (typeOf(operand) != match) {
-            return &RuntimeError{operator, "Operand(s) must be a " + eMsg}
+        str, ok := ret.(string)
+        if !ok {
+            return "", &RuntimeError{method.declaration.Name, "__str must return a string"}
         }
+        return str, nil
+    case *LoxList:
+        return val.format(i.stringifyElement)
+    case *LoxMap:
+        return val.format(i.stringifyElement)
     }
-    
-    return nil
+
+    return stringify(obj), nil
+}
+
+// function to stringify a value nested in a collection, quoting strings
+func (i Interpreter) stringifyElement(obj Object) (string, error) {
+    if str, ok := obj.(string); ok {
+        return "\"" + str + "\"", nil
+    }
+
+    return i.stringify(obj)
+}
+
+// function to check if a value can be concatenated onto a string
+func (i Interpreter) isPrintable(obj Object) bool {
+    if instance, ok := obj.(*LoxInstance); ok {
+        return instance.class.FindMethod("__str") != nil
+    }
+
+    return isString(obj) || isNumber(obj)
+}
+
+func isString(obj Object) bool {
+    _, ok := obj.(string)
+    return ok
 }
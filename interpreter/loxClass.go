@@ -0,0 +1,94 @@
+package interpreter
+
+import (
+    . "glox/util"
+    . "glox/token"
+    . "glox/loxError"
+)
+
+type LoxClass struct {
+    Name string
+    superclass *LoxClass
+    methods map[string]*LoxFunction
+    getters map[string]*LoxFunction
+    statics map[string]*LoxFunction
+}
+
+func NewLoxClass(name string, superclass *LoxClass, methods, getters, statics map[string]*LoxFunction) *LoxClass {
+    return &LoxClass{
+        Name: name,
+        superclass: superclass,
+        methods: methods,
+        getters: getters,
+        statics: statics,
+    }
+}
+
+// function to look up a method declared on the class
+// recursively check the superclass chain if not found
+func (c *LoxClass) FindMethod(name string) *LoxFunction {
+    if method, ok := c.methods[name]; ok {
+        return method
+    }
+
+    if c.superclass != nil {
+        return c.superclass.FindMethod(name)
+    }
+
+    return nil
+}
+
+// function to look up a getter declared on the class or its superclasses
+func (c *LoxClass) FindGetter(name string) *LoxFunction {
+    if getter, ok := c.getters[name]; ok {
+        return getter
+    }
+
+    if c.superclass != nil {
+        return c.superclass.FindGetter(name)
+    }
+
+    return nil
+}
+
+// function to retrieve a static method, which are inherited like methods
+func (c *LoxClass) Get(name Token) (Object, error) {
+    for class := c; class != nil; class = class.superclass {
+        if method, ok := class.statics[name.Lexeme]; ok {
+            return method, nil
+        }
+    }
+
+    return nil, &RuntimeError{name, "Undefined static method '" + name.Lexeme + "'"}
+}
+
+// calling a class constructs a new instance and runs its initializer
+func (c *LoxClass) Call(i Interpreter, args []Object) (Object, error) {
+    instance := NewLoxInstance(c)
+    if initializer := c.FindMethod("init"); initializer != nil {
+        _, err := initializer.Bind(instance).Call(i, args)
+        if err != nil { return nil, err }
+    }
+
+    return instance, nil
+}
+
+func (c *LoxClass) MinArity() int {
+    if initializer := c.FindMethod("init"); initializer != nil {
+        return initializer.MinArity()
+    }
+
+    return 0
+}
+
+func (c *LoxClass) MaxArity() int {
+    if initializer := c.FindMethod("init"); initializer != nil {
+        return initializer.MaxArity()
+    }
+
+    return 0
+}
+
+func (c *LoxClass) ToString() string {
+    return c.Name
+}
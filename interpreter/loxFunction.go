@@ -11,33 +11,104 @@ import (
 type LoxFunction struct {
     declaration Function
     closure *Environment
+    isInitializer bool
 }
 
-func NewLoxFunction(decl Function, closure *Environment) *LoxFunction {
-    return &LoxFunction{ declaration: decl, closure: closure }
+func NewLoxFunction(decl Function, closure *Environment, isInitializer bool) *LoxFunction {
+    return &LoxFunction{ declaration: decl, closure: closure, isInitializer: isInitializer }
 }
 
+// function to create a copy of the method with "this" bound to the instance
+func (f LoxFunction) Bind(instance *LoxInstance) *LoxFunction {
+    env := NewEnvironment(f.closure)
+    env.Define("this", instance)
+    return NewLoxFunction(f.declaration, env, f.isInitializer)
+}
+
+// tail calls come back from the body as a TailCallError and are run here
+// in a loop instead of nesting, so tail recursion runs in constant stack
 func (f LoxFunction) Call(i Interpreter, args []Object) (Object, error) {
+    for {
+        ret, err := f.call(i, args)
+        var tc *TailCallError
+        if !errors.As(err, &tc) {
+            return ret, err
+        }
+
+        f, args = *tc.Callee.(*LoxFunction), tc.Args
+    }
+}
+
+// function to run the body once with the given arguments
+func (f LoxFunction) call(i Interpreter, args []Object) (Object, error) {
     env := NewEnvironment(f.closure)
-    for k := 0; k < len(f.declaration.Params); k++ {
-        env.Define(f.declaration.Params[k].Lexeme, args[k])
+
+    // defaults are evaluated per call inside the new scope so they can
+    // refer to the parameters before them
+    i.env = env
+    for k, param := range f.declaration.Params {
+        if k < len(args) {
+            env.Define(param.Lexeme, args[k])
+            continue
+        }
+
+        val, err := i.evaluate(f.declaration.Defaults[k])
+        if err != nil { return nil, err }
+        env.Define(param.Lexeme, val)
+    }
+
+    if f.declaration.Rest.Lexeme != "" {
+        rest := make([]Object, 0)
+        if len(args) > len(f.declaration.Params) {
+            rest = append(rest, args[len(f.declaration.Params):]...)
+        }
+        env.Define(f.declaration.Rest.Lexeme, NewLoxList(rest))
     }
 
     err := i.executeBlock(f.declaration.Body, env)
     var re *ReturnError
     if errors.As(err, &re) {
+        if f.isInitializer {
+            return f.this()
+        }
         return re.Value, nil
     } else if err != nil { 
         return nil, err
     }
 
+    if f.isInitializer {
+        return f.this()
+    }
     return nil, nil
 }
 
-func (f LoxFunction) Arity() int {
+// function to fetch the instance an initializer is bound to
+func (f LoxFunction) this() (Object, error) {
+    return f.closure.GetAt(0, "this"), nil
+}
+
+func (f LoxFunction) MinArity() int {
+    min := 0
+    for _, def := range f.declaration.Defaults {
+        if def == nil {
+            min++
+        }
+    }
+
+    return min
+}
+
+func (f LoxFunction) MaxArity() int {
+    if f.declaration.Rest.Lexeme != "" {
+        return VARIADIC
+    }
+
     return len(f.declaration.Params)
 }
 
 func (f LoxFunction) ToString() string {
+    if f.declaration.Name.Lexeme == "" {
+        return "<anonymous fn>"
+    }
     return "<fn " + f.declaration.Name.Lexeme + ">"
 }
@@ -0,0 +1,38 @@
+package interpreter
+
+import (
+    . "glox/util"
+    . "glox/token"
+    . "glox/loxError"
+)
+
+type LoxInstance struct {
+    class *LoxClass
+    fields map[string]Object
+}
+
+func NewLoxInstance(class *LoxClass) *LoxInstance {
+    return &LoxInstance{ class: class, fields: make(map[string]Object) }
+}
+
+// function to retrieve a property, fields shadow methods
+func (l *LoxInstance) Get(name Token) (Object, error) {
+    if val, ok := l.fields[name.Lexeme]; ok {
+        return val, nil
+    }
+
+    if method := l.class.FindMethod(name.Lexeme); method != nil {
+        return method.Bind(l), nil
+    }
+
+    return nil, &RuntimeError{name, "Undefined property '" + name.Lexeme + "'"}
+}
+
+// function to set a field, creating it if it doesn't exist
+func (l *LoxInstance) Set(name Token, value Object) {
+    l.fields[name.Lexeme] = value
+}
+
+func (l *LoxInstance) ToString() string {
+    return l.class.Name + " instance"
+}
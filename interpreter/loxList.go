@@ -0,0 +1,82 @@
+package interpreter
+
+import (
+    . "glox/util"
+    . "glox/token"
+    . "glox/loxError"
+    "strings"
+)
+
+type LoxList struct {
+    Elements []Object
+}
+
+func NewLoxList(elements []Object) *LoxList {
+    return &LoxList{ Elements: elements }
+}
+
+// function to fetch the element at an index
+func (l *LoxList) Get(bracket Token, index Object) (Object, error) {
+    k, err := checkIndex(bracket, index, len(l.Elements))
+    if err != nil { return nil, err }
+
+    return l.Elements[k], nil
+}
+
+// function to replace the element at an index
+func (l *LoxList) Set(bracket Token, index Object, value Object) error {
+    k, err := checkIndex(bracket, index, len(l.Elements))
+    if err != nil { return err }
+
+    l.Elements[k] = value
+    return nil
+}
+
+func (l *LoxList) ToString() string {
+    str, _ := l.format(plainElement)
+    return str
+}
+
+// function to build the list's string with the given element formatter
+func (l *LoxList) format(element elementFormatter) (string, error) {
+    elements := make([]string, len(l.Elements))
+    for k, val := range l.Elements {
+        str, err := element(val)
+        if err != nil { return "", err }
+        elements[k] = str
+    }
+
+    return "[" + strings.Join(elements, ", ") + "]", nil
+}
+
+// function to verify an index is a whole number inside [0, length)
+func checkIndex(bracket Token, index Object, length int) (int, error) {
+    num, ok := toInt(index)
+    if !ok {
+        return 0, &RuntimeError{bracket, "Index must be a whole number"}
+    }
+
+    if num < 0 || num >= int64(length) {
+        return 0, &RuntimeError{bracket, "Index out of range"}
+    }
+
+    return int(num), nil
+}
+
+// function converting a value nested in a collection to a string
+type elementFormatter func(obj Object) (string, error)
+
+// elementFormatter for when no interpreter is around to run __str
+func plainElement(obj Object) (string, error) {
+    return stringifyElement(obj), nil
+}
+
+// function to stringify a value nested in a collection
+// strings are quoted so they can be told apart from other values
+func stringifyElement(obj Object) string {
+    if str, ok := obj.(string); ok {
+        return "\"" + str + "\""
+    }
+
+    return stringify(obj)
+}
@@ -0,0 +1,130 @@
+package interpreter
+
+import (
+    . "glox/util"
+    . "glox/token"
+    . "glox/loxError"
+    "strings"
+)
+
+// keys are kept in insertion order so printing and iterating are stable
+// whole float keys are stored as integers so that m[1] and m[1.0] agree
+type LoxMap struct {
+    keys []Object
+    values map[Object]Object
+}
+
+func NewLoxMap() *LoxMap {
+    return &LoxMap{ keys: make([]Object, 0), values: make(map[Object]Object) }
+}
+
+// function to fetch the value stored under a key
+func (m *LoxMap) Get(bracket Token, key Object) (Object, error) {
+    err := checkKey(bracket, key)
+    if err != nil { return nil, err }
+
+    val, ok := m.values[normalizeKey(key)]
+    if !ok {
+        return nil, &RuntimeError{bracket, "Undefined key " + stringifyElement(key)}
+    }
+
+    return val, nil
+}
+
+// function to store a value under a key, adding the key if it is new
+func (m *LoxMap) Set(bracket Token, key Object, value Object) error {
+    err := checkKey(bracket, key)
+    if err != nil { return err }
+
+    m.Put(key, value)
+    return nil
+}
+
+// function to store a value under a key that is already known to be valid
+func (m *LoxMap) Put(key Object, value Object) {
+    key = normalizeKey(key)
+    if _, ok := m.values[key]; !ok {
+        m.keys = append(m.keys, key)
+    }
+    m.values[key] = value
+}
+
+func (m *LoxMap) Has(key Object) bool {
+    _, ok := m.values[normalizeKey(key)]
+    return ok
+}
+
+// function to remove a key, doing nothing if it isn't present
+func (m *LoxMap) Delete(key Object) {
+    if !m.Has(key) {
+        return
+    }
+    key = normalizeKey(key)
+
+    delete(m.values, key)
+    for k, existing := range m.keys {
+        if existing == key {
+            m.keys = append(m.keys[:k], m.keys[k + 1:]...)
+            break
+        }
+    }
+}
+
+// function to return a copy of the keys in insertion order
+func (m *LoxMap) Keys() []Object {
+    keys := make([]Object, len(m.keys))
+    copy(keys, m.keys)
+    return keys
+}
+
+func (m *LoxMap) Len() int {
+    return len(m.keys)
+}
+
+func (m *LoxMap) ToString() string {
+    str, _ := m.format(plainElement)
+    return str
+}
+
+// function to build the map's string with the given element formatter
+func (m *LoxMap) format(element elementFormatter) (string, error) {
+    entries := make([]string, len(m.keys))
+    for k, key := range m.keys {
+        keyStr, err := element(key)
+        if err != nil { return "", err }
+        valStr, err := element(m.values[key])
+        if err != nil { return "", err }
+        entries[k] = keyStr + ": " + valStr
+    }
+
+    return "{" + strings.Join(entries, ", ") + "}", nil
+}
+
+// function to check a key is a value that can be compared with isEqual
+func checkKey(token Token, key Object) error {
+    if !isValidKey(key) {
+        return &RuntimeError{token, "Map keys must be numbers, strings, booleans or nil"}
+    }
+
+    return nil
+}
+
+func isValidKey(key Object) bool {
+    switch key.(type) {
+    case nil, int64, float64, string, bool:
+        return true
+    }
+
+    return false
+}
+
+// function to give keys that are equal under isEqual the same representation
+func normalizeKey(key Object) Object {
+    if num, ok := key.(float64); ok {
+        if whole, ok := toInt(num); ok {
+            return whole
+        }
+    }
+
+    return key
+}
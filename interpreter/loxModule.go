@@ -0,0 +1,143 @@
+package interpreter
+
+import (
+    . "glox/ast"
+    . "glox/util"
+    . "glox/token"
+    . "glox/environment"
+    . "glox/loxError"
+    "path/filepath"
+    "strings"
+)
+
+// Function that reads, parses and resolves the file at path
+// supplied by the caller since those passes live outside this package
+type ModuleLoader func(path string) ([]Stmt, error)
+
+// state shared by every copy of the Interpreter
+type modules struct {
+    load ModuleLoader
+    // path of the module currently running its top-level code,
+    // empty when it is code typed at the prompt
+    file string
+    // modules by absolute path, including ones still being loaded
+    cache map[string]*LoxModule
+}
+
+func newModules() *modules {
+    return &modules{ cache: make(map[string]*LoxModule) }
+}
+
+// function to return the directory relative imports start from
+func (m *modules) dir() string {
+    if m.file == "" {
+        return "."
+    }
+    return filepath.Dir(m.file)
+}
+
+// function to describe the module running an import, for error messages
+func (m *modules) importer() string {
+    if m.file == "" {
+        return " (imported from the prompt)"
+    }
+    return " (imported from " + m.file + ")"
+}
+
+// function to set how modules are loaded and the file running the top level,
+// empty for the prompt, which relative imports start from
+func (i Interpreter) SetModuleLoader(load ModuleLoader, file string) {
+    i.modules.load = load
+    i.modules.file = file
+    if file == "" {
+        return
+    }
+
+    // the entry script is a module that stays loading while it runs,
+    // so a module importing it back is caught as a cycle
+    if path, err := filepath.Abs(file); err == nil {
+        i.modules.cache[path] = &LoxModule{ Name: moduleName(path), env: i.env, loading: true }
+    }
+}
+
+// function to name a module after its file, without the extension
+func moduleName(path string) string {
+    return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+type LoxModule struct {
+    Name string
+    env *Environment
+    loading bool
+}
+
+// function to look up a top-level definition of the module
+func (m *LoxModule) Get(name Token) (Object, error) {
+    val, err := m.env.Get(name)
+    if err == nil && !isNative(name.Lexeme, val) {
+        return val, nil
+    }
+
+    return nil, &RuntimeError{name, "Undefined property '" + name.Lexeme + "' in module " + m.Name}
+}
+
+// function to return the module's own top-level definitions, without natives
+func (m *LoxModule) Definitions() map[string]Object {
+    values := m.env.Values()
+    for name, value := range values {
+        if isNative(name, value) {
+            delete(values, name)
+        }
+    }
+
+    return values
+}
+
+func (m *LoxModule) ToString() string {
+    return "<module " + m.Name + ">"
+}
+
+// function to run the module at path, relative to the importing module,
+// the first time it is imported and return the cached module after that
+func (i Interpreter) importModule(keyword Token, path string) (*LoxModule, error) {
+    if !filepath.IsAbs(path) {
+        path = filepath.Join(i.modules.dir(), path)
+    }
+    path, err := filepath.Abs(path)
+    if err != nil {
+        return nil, &RuntimeError{keyword, "Could not resolve module path: " + err.Error() + i.modules.importer()}
+    }
+
+    if module, ok := i.modules.cache[path]; ok {
+        if module.loading {
+            return nil, &RuntimeError{keyword, "Import cycle detected at module " + module.Name + i.modules.importer()}
+        }
+        return module, nil
+    }
+
+    if i.modules.load == nil {
+        return nil, &RuntimeError{keyword, "Modules can't be imported here"}
+    }
+    statements, err := i.modules.load(path)
+    if err != nil {
+        return nil, &RuntimeError{keyword, "Could not load module: " + err.Error() + i.modules.importer()}
+    }
+
+    env := NewEnvironment()
+    defineNatives(env)
+    module := &LoxModule{ Name: moduleName(path), env: env, loading: true }
+    i.modules.cache[path] = module
+
+    enclosingFile := i.modules.file
+    i.modules.file = path
+    err = i.executeBlock(statements, env)
+    i.modules.file = enclosingFile
+    module.loading = false
+
+    if err != nil {
+        // a module that failed part way is forgotten so it isn't half-used later
+        delete(i.modules.cache, path)
+        return nil, err
+    }
+    return module, nil
+}
@@ -0,0 +1,74 @@
+package interpreter
+
+import (
+    . "glox/util"
+    . "glox/loxError"
+    "fmt"
+)
+
+// Lazy sequence of whole numbers from start up to, but excluding, end
+type LoxRange struct {
+    start int64
+    end int64
+    step int64
+}
+
+func NewLoxRange(start int64, end int64, step int64) *LoxRange {
+    return &LoxRange{ start: start, end: end, step: step }
+}
+
+// function to check if a value lies inside the range in the step's direction
+func (r *LoxRange) contains(val int64) bool {
+    if r.step > 0 {
+        return val < r.end
+    }
+    return val > r.end
+}
+
+// function to step past a value, ok is false if the next value would overflow
+func (r *LoxRange) next(val int64) (int64, bool) {
+    sum := val + r.step
+    if (val >= 0) == (r.step >= 0) && (sum >= 0) != (val >= 0) {
+        return 0, false
+    }
+    return sum, true
+}
+
+func (r *LoxRange) ToString() string {
+    return fmt.Sprintf("range(%v, %v, %v)", r.start, r.end, r.step)
+}
+
+// range(end), range(start, end) or range(start, end, step)
+type Range struct {}
+
+func (r Range) MinArity() int {
+    return 1
+}
+
+func (r Range) MaxArity() int {
+    return 3
+}
+
+func (r Range) Call(i Interpreter, args []Object) (Object, error) {
+    bounds := []int64{0, 0, 1}
+    for k, arg := range args {
+        num, ok := toInt(arg)
+        if !ok {
+            return nil, &NativeError{"Arguments to range must be whole numbers"}
+        }
+        bounds[k] = num
+    }
+
+    if len(args) == 1 {
+        bounds[0], bounds[1] = 0, bounds[0]
+    }
+    if bounds[2] == 0 {
+        return nil, &NativeError{"Range step can't be zero"}
+    }
+
+    return NewLoxRange(bounds[0], bounds[1], bounds[2]), nil
+}
+
+func (r Range) ToString() string {
+    return "<native fn>"
+}
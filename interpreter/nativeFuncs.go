@@ -2,12 +2,45 @@ package interpreter
 
 import (
     . "glox/util"
+    . "glox/environment"
+    . "glox/loxError"
     "time"
+    "unicode/utf8"
 )
 
+// natives defined in the global scope of every module
+var natives = map[string]Callable{
+    "clock": Clock{},
+    "len": Len{},
+    "append": Append{},
+    "pop": Pop{},
+    "insert": Insert{},
+    "remove": Remove{},
+    "has": Has{},
+    "keys": Keys{},
+    "delete": Delete{},
+    "range": Range{},
+}
+
+func defineNatives(env *Environment) {
+    for name, native := range natives {
+        env.Define(name, native)
+    }
+}
+
+// function to check if a global still holds the native it started with
+func isNative(name string, value Object) bool {
+    native, ok := natives[name]
+    return ok && native == value
+}
+
 type Clock struct {}
 
-func (c Clock) Arity() int {
+func (c Clock) MinArity() int {
+    return 0
+}
+
+func (c Clock) MaxArity() int {
     return 0
 }
 
@@ -18,3 +51,238 @@ func (c Clock) Call(i Interpreter, args []Object) (Object, error) {
 func (c Clock) ToString() string {
     return "<native fn>"
 }
+
+// len(value): number of elements in a list, entries in a map or characters in a string
+type Len struct {}
+
+func (l Len) MinArity() int {
+    return 1
+}
+
+func (l Len) MaxArity() int {
+    return 1
+}
+
+func (l Len) Call(i Interpreter, args []Object) (Object, error) {
+    switch val := args[0].(type) {
+    case *LoxList:
+        return int64(len(val.Elements)), nil
+    case *LoxMap:
+        return int64(val.Len()), nil
+    case string:
+        return int64(utf8.RuneCountInString(val)), nil
+    }
+
+    return nil, &NativeError{"Can only take the length of a list, map or string"}
+}
+
+func (l Len) ToString() string {
+    return "<native fn>"
+}
+
+// append(list, values...): add each value to the end of list
+type Append struct {}
+
+func (a Append) MinArity() int {
+    return 2
+}
+
+func (a Append) MaxArity() int {
+    return VARIADIC
+}
+
+func (a Append) Call(i Interpreter, args []Object) (Object, error) {
+    list, err := listArg(args[0], "append")
+    if err != nil { return nil, err }
+
+    list.Elements = append(list.Elements, args[1:]...)
+    return nil, nil
+}
+
+func (a Append) ToString() string {
+    return "<native fn>"
+}
+
+// pop(list): remove and return the last element of list
+type Pop struct {}
+
+func (p Pop) MinArity() int {
+    return 1
+}
+
+func (p Pop) MaxArity() int {
+    return 1
+}
+
+func (p Pop) Call(i Interpreter, args []Object) (Object, error) {
+    list, err := listArg(args[0], "pop")
+    if err != nil { return nil, err }
+
+    if len(list.Elements) == 0 {
+        return nil, &NativeError{"Can't pop from an empty list"}
+    }
+
+    last := list.Elements[len(list.Elements) - 1]
+    list.Elements = list.Elements[:len(list.Elements) - 1]
+    return last, nil
+}
+
+func (p Pop) ToString() string {
+    return "<native fn>"
+}
+
+// insert(list, index, value): insert value before index, index may equal len(list)
+type Insert struct {}
+
+func (n Insert) MinArity() int {
+    return 3
+}
+
+func (n Insert) MaxArity() int {
+    return 3
+}
+
+func (n Insert) Call(i Interpreter, args []Object) (Object, error) {
+    list, err := listArg(args[0], "insert")
+    if err != nil { return nil, err }
+
+    k, err := nativeIndex(args[1], len(list.Elements) + 1)
+    if err != nil { return nil, err }
+
+    list.Elements = append(list.Elements, nil)
+    copy(list.Elements[k + 1:], list.Elements[k:])
+    list.Elements[k] = args[2]
+    return nil, nil
+}
+
+func (n Insert) ToString() string {
+    return "<native fn>"
+}
+
+// remove(list, index): remove and return the element at index
+type Remove struct {}
+
+func (r Remove) MinArity() int {
+    return 2
+}
+
+func (r Remove) MaxArity() int {
+    return 2
+}
+
+func (r Remove) Call(i Interpreter, args []Object) (Object, error) {
+    list, err := listArg(args[0], "remove")
+    if err != nil { return nil, err }
+
+    k, err := nativeIndex(args[1], len(list.Elements))
+    if err != nil { return nil, err }
+
+    removed := list.Elements[k]
+    list.Elements = append(list.Elements[:k], list.Elements[k + 1:]...)
+    return removed, nil
+}
+
+func (r Remove) ToString() string {
+    return "<native fn>"
+}
+
+// has(map, key): whether key is present in map
+type Has struct {}
+
+func (h Has) MinArity() int {
+    return 2
+}
+
+func (h Has) MaxArity() int {
+    return 2
+}
+
+func (h Has) Call(i Interpreter, args []Object) (Object, error) {
+    m, err := mapArg(args[0], "has")
+    if err != nil { return nil, err }
+
+    return m.Has(args[1]), nil
+}
+
+func (h Has) ToString() string {
+    return "<native fn>"
+}
+
+// keys(map): list of the keys in map in insertion order
+type Keys struct {}
+
+func (k Keys) MinArity() int {
+    return 1
+}
+
+func (k Keys) MaxArity() int {
+    return 1
+}
+
+func (k Keys) Call(i Interpreter, args []Object) (Object, error) {
+    m, err := mapArg(args[0], "keys")
+    if err != nil { return nil, err }
+
+    return NewLoxList(m.Keys()), nil
+}
+
+func (k Keys) ToString() string {
+    return "<native fn>"
+}
+
+// delete(map, key): remove key and its value from map
+type Delete struct {}
+
+func (d Delete) MinArity() int {
+    return 2
+}
+
+func (d Delete) MaxArity() int {
+    return 2
+}
+
+func (d Delete) Call(i Interpreter, args []Object) (Object, error) {
+    m, err := mapArg(args[0], "delete")
+    if err != nil { return nil, err }
+
+    m.Delete(args[1])
+    return nil, nil
+}
+
+func (d Delete) ToString() string {
+    return "<native fn>"
+}
+
+// function to check that a native's argument is a list
+func listArg(arg Object, name string) (*LoxList, error) {
+    list, ok := arg.(*LoxList)
+    if !ok {
+        return nil, &NativeError{"First argument to " + name + " must be a list"}
+    }
+
+    return list, nil
+}
+
+// function to check that a native's argument is a map
+func mapArg(arg Object, name string) (*LoxMap, error) {
+    m, ok := arg.(*LoxMap)
+    if !ok {
+        return nil, &NativeError{"First argument to " + name + " must be a map"}
+    }
+
+    return m, nil
+}
+
+// function to check that a native's index argument lies inside [0, length)
+func nativeIndex(index Object, length int) (int, error) {
+    num, ok := toInt(index)
+    if !ok {
+        return 0, &NativeError{"Index must be a whole number"}
+    }
+
+    if num < 0 || num >= int64(length) {
+        return 0, &NativeError{"Index out of range"}
+    }
+
+    return int(num), nil
+}
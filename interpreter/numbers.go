@@ -0,0 +1,232 @@
+package interpreter
+
+import (
+    . "glox/util"
+    . "glox/token"
+    . "glox/loxError"
+    "math"
+    "strconv"
+)
+
+// Numbers are either int64, for literals without a decimal point, or float64
+
+func isNumber(obj Object) bool {
+    switch obj.(type) {
+    case int64, float64:
+        return true
+    }
+
+    return false
+}
+
+// function to widen a number to float64
+func toFloat(obj Object) float64 {
+    if num, ok := obj.(int64); ok {
+        return float64(num)
+    }
+
+    return obj.(float64)
+}
+
+// function to convert a whole number of either type to an int64
+func toInt(obj Object) (int64, bool) {
+    switch num := obj.(type) {
+    case int64:
+        return num, true
+    case float64:
+        if num == math.Trunc(num) && num >= math.MinInt64 && num < math.MaxInt64 {
+            return int64(num), true
+        }
+    }
+
+    return 0, false
+}
+
+// function to verify that all operands are numbers
+func verifyNumbers(operator Token, operands ...Object) error {
+    for _, operand := range operands {
+        if !isNumber(operand) {
+            return &RuntimeError{operator, "Operand(s) must be a number"}
+        }
+    }
+
+    return nil
+}
+
+// function to apply an arithmetic or comparison operator to two integers
+// results that don't fit an int64 are promoted to float64 rather than wrapping
+func intBinary(operator Token, left, right int64) (Object, error) {
+    switch operator.Type {
+    case GREAT:
+        return left > right, nil
+    case GREAT_EQUAL:
+        return left >= right, nil
+    case LESS:
+        return left < right, nil
+    case LESS_EQUAL:
+        return left <= right, nil
+    case MINUS:
+        diff := left - right
+        if (left >= 0) != (right >= 0) && (diff >= 0) != (left >= 0) {
+            return float64(left) - float64(right), nil
+        }
+        return diff, nil
+    case PLUS:
+        sum := left + right
+        if (left >= 0) == (right >= 0) && (sum >= 0) != (left >= 0) {
+            return float64(left) + float64(right), nil
+        }
+        return sum, nil
+    case STAR:
+        if product, ok := mulInt(left, right); ok {
+            return product, nil
+        }
+        return float64(left) * float64(right), nil
+
+    case SLASH:
+        // "/" is always true division, "~/" is the integer one
+        if right == 0 {
+            return nil, &RuntimeError{operator, "Cannot divide by zero"}
+        }
+        return float64(left) / float64(right), nil
+
+    case PERCENT:
+        if right == 0 {
+            return nil, &RuntimeError{operator, "Cannot divide by zero"}
+        }
+
+        // floored modulo so that a == (a ~/ b) * b + a % b
+        mod := left % right
+        if mod != 0 && (mod < 0) != (right < 0) {
+            mod += right
+        }
+        return mod, nil
+
+    case TILDE_SLASH:
+        if right == 0 {
+            return nil, &RuntimeError{operator, "Cannot divide by zero"}
+        }
+
+        if left == math.MinInt64 && right == -1 {
+            return -float64(left), nil
+        }
+
+        // Go truncates towards zero, step down when the signs differ
+        quot := left / right
+        if left % right != 0 && (left < 0) != (right < 0) {
+            quot--
+        }
+        return quot, nil
+
+    case STAR_STAR:
+        if right < 0 {
+            return math.Pow(float64(left), float64(right)), nil
+        }
+
+        // exponentiation by squaring keeps the result exact
+        ret := int64(1)
+        baseOverflowed := false
+        for base, exp := left, right; exp > 0; exp >>= 1 {
+            if exp & 1 == 1 {
+                var ok bool
+                if ret, ok = mulInt(ret, base); !ok || baseOverflowed {
+                    return math.Pow(float64(left), float64(right)), nil
+                }
+            }
+            if exp > 1 {
+                var ok bool
+                if base, ok = mulInt(base, base); !ok {
+                    // only matters if a later bit still multiplies it in
+                    baseOverflowed = true
+                }
+            }
+        }
+        return ret, nil
+    }
+
+    return nil, nil
+}
+
+// function to multiply two integers, ok is false if the result overflows
+func mulInt(left, right int64) (int64, bool) {
+    if left == 0 || right == 0 {
+        return 0, true
+    }
+
+    product := left * right
+    if product / right != left || (left == -1 && right == math.MinInt64) || (right == -1 && left == math.MinInt64) {
+        return 0, false
+    }
+    return product, true
+}
+
+// function to negate a number, promoting the one int64 that can't be negated
+func negate(num Object) Object {
+    if n, ok := num.(int64); ok {
+        if n == math.MinInt64 {
+            return -float64(n)
+        }
+        return -n
+    }
+
+    return -(num.(float64))
+}
+
+// function to format a float, only huge or tiny magnitudes use an exponent
+func formatFloat(num float64) string {
+    abs := math.Abs(num)
+    if abs >= 1e21 || (abs != 0 && abs < 1e-6) {
+        return strconv.FormatFloat(num, 'g', -1, 64)
+    }
+
+    return strconv.FormatFloat(num, 'f', -1, 64)
+}
+
+// function to apply an arithmetic or comparison operator to two floats
+func floatBinary(operator Token, left, right float64) (Object, error) {
+    switch operator.Type {
+    case GREAT:
+        return left > right, nil
+    case GREAT_EQUAL:
+        return left >= right, nil
+    case LESS:
+        return left < right, nil
+    case LESS_EQUAL:
+        return left <= right, nil
+    case MINUS:
+        return left - right, nil
+    case PLUS:
+        return left + right, nil
+    case STAR:
+        return left * right, nil
+
+    case SLASH:
+        if right == 0 {
+            return nil, &RuntimeError{operator, "Cannot divide by zero"}
+        }
+        return left / right, nil
+
+    case PERCENT:
+        if right == 0 {
+            return nil, &RuntimeError{operator, "Cannot divide by zero"}
+        }
+
+        // floored modulo so that a == (a ~/ b) * b + a % b
+        mod := math.Mod(left, right)
+        if mod != 0 && (mod < 0) != (right < 0) {
+            mod += right
+        }
+        return mod, nil
+
+    case TILDE_SLASH:
+        if right == 0 {
+            return nil, &RuntimeError{operator, "Cannot divide by zero"}
+        }
+        return math.Floor(left / right), nil
+
+    case STAR_STAR:
+        return math.Pow(left, right), nil
+    }
+
+    return nil, nil
+}
@@ -5,10 +5,13 @@ import (
     "os"
     "bufio"
     "io"
+    "errors"
     "glox/util"
+    "glox/ast"
     "glox/scanner"
     "glox/parser"
     "glox/interpreter"
+    "glox/resolver"
     // "glox/token"
 )
 
@@ -19,8 +22,10 @@ func main() {
         fmt.Printf("Usage: %v <script>\n", os.Args[0])
         os.Exit(64)
     } else if len(os.Args) == 2 {
+        interpret.SetModuleLoader(loadModule, os.Args[1])
         runFile(os.Args[1])
     } else {
+        interpret.SetModuleLoader(loadModule, "")
         runPrompt()
     }
 }
@@ -54,6 +59,32 @@ func runPrompt() {
     }
 } 
 
+// scan, parse and resolve an imported module for the interpreter to run
+func loadModule(path string) ([]ast.Stmt, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+
+    scan := scanner.NewScanner(string(data))
+    tokens := scan.ScanTokens()
+    parse := parser.NewParser(tokens)
+    statements := parse.Parse()
+
+    if (util.HadError) {
+        return nil, errors.New("syntax errors in " + path)
+    }
+
+    resolve := resolver.NewResolver(interpret)
+    resolve.Resolve(statements)
+
+    if (util.HadError) {
+        return nil, errors.New("errors in " + path)
+    }
+
+    return statements, nil
+}
+
 // scan a line received from runPrompt() or runFile()
 func run(src string) {
     scan := scanner.NewScanner(src)
@@ -65,6 +96,13 @@ func run(src string) {
         return
     }
 
+    resolve := resolver.NewResolver(interpret)
+    resolve.Resolve(statements)
+
+    if (util.HadError) {
+        return
+    }
+
     interpret.Interpret(statements)
 }
 
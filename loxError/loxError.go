@@ -21,6 +21,26 @@ func ErrorRuntime(error RuntimeError) {
     HadRuntimeError = true
 }
 
+// Error carrying a value thrown by a "throw" statement
+type ThrowError struct {
+    Keyword Token
+    Value Object
+}
+
+func (e *ThrowError) Error() string {
+    return fmt.Sprintf("%v - %v", e.Keyword, e.Value)
+}
+
+// Error raised by native functions, which have no token of their own
+// the interpreter turns it into a RuntimeError at the call site
+type NativeError struct {
+    Msg string
+}
+
+func (e *NativeError) Error() string {
+    return e.Msg
+}
+
 type ReturnError struct {
     Value Object
 }
@@ -28,3 +48,38 @@ type ReturnError struct {
 func (e *ReturnError) Error() string {
     return fmt.Sprintf("%v", e.Value)
 }
+
+// Error carrying a call made in tail position, which the function
+// returning it runs in place of itself
+type TailCallError struct {
+    Paren Token
+    Callee Object
+    Args []Object
+}
+
+func (e *TailCallError) Error() string {
+    return fmt.Sprintf("%v - tail call outside of a function", e.Paren)
+}
+
+// Error raised when a "?." finds nil, caught by the chain it belongs to
+type NilChainError struct {}
+
+func (e *NilChainError) Error() string {
+    return "optional chain short-circuited outside of a chain"
+}
+
+type BreakError struct {
+    Keyword Token
+}
+
+func (e *BreakError) Error() string {
+    return fmt.Sprintf("%v - break outside of a loop", e.Keyword)
+}
+
+type ContinueError struct {
+    Keyword Token
+}
+
+func (e *ContinueError) Error() string {
+    return fmt.Sprintf("%v - continue outside of a loop", e.Keyword)
+}
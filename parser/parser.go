@@ -11,6 +11,10 @@ import (
 type Parser struct {
     tokens []Token
     curr int
+    // number of loops enclosing the current statement, for break/continue
+    loopDepth int
+    // number of try and catch blocks enclosing the current statement
+    tryDepth int
 }
 
 type ParseError struct {
@@ -24,7 +28,7 @@ func (e *ParseError) Error() string {
 
 // Parser "constructor"
 func NewParser(tokens []Token) *Parser {
-    return &Parser{tokens, 0}
+    return &Parser{tokens, 0, 0, 0}
 }
 
 // function to start parsing tokens
@@ -42,9 +46,27 @@ func (p *Parser) Parse() []Stmt {
     return ret
 }
 
-// RULE declaration: function | varDecl | statement
+// RULE declaration: classDecl | function | varDecl | constDecl | importDecl | statement
 func (p *Parser) declaration() (Stmt, error) {
-    if p.match(FUN) {
+    if p.match(IMPORT) {
+        ret, err := p.importDecl()
+        if err != nil {
+            p.synchronize()
+            return nil, err
+        }
+        return ret, nil
+    }
+    if p.match(CLASS) {
+        ret, err := p.classDecl()
+        if err != nil {
+            p.synchronize()
+            return nil, err
+        }
+        return ret, nil
+    }
+    // "fun" without a name is an anonymous function used as an expression
+    if p.check(FUN) && p.checkNext(IDENTIFIER) {
+        p.advance()
         ret, err := p.function("function")
         if err != nil {
             p.synchronize()
@@ -60,6 +82,14 @@ func (p *Parser) declaration() (Stmt, error) {
         }
         return ret, nil
     }
+    if p.match(CONST) {
+        ret, err := p.constDecl()
+        if err != nil {
+            p.synchronize()
+            return nil, err
+        }
+        return ret, nil
+    }
 
     ret, err := p.statement()
     if err != nil {
@@ -69,38 +99,172 @@ func (p *Parser) declaration() (Stmt, error) {
     return ret, nil
 }
 
-// RULE: function: IDENTIFIER "(" parameters? ")" block
-func (p *Parser) function(kind string) (Stmt, error) {
-    name, err := p.consume(IDENTIFIER, "Expect " + kind + " name")
+// RULE importDecl: "import" STRING ( "as" IDENTIFIER )? ";"
+// "as" is only special here so it stays usable as a variable name
+func (p *Parser) importDecl() (Stmt, error) {
+    keyword := p.previous()
+    path, err := p.consume(STRING, "Expect module path after 'import'")
+    if err != nil { return nil, err }
+
+    var name Token
+    if p.check(IDENTIFIER) && p.peek().Lexeme == "as" {
+        p.advance()
+        name, err = p.consume(IDENTIFIER, "Expect module name after 'as'")
+        if err != nil { return nil, err }
+    }
+
+    _, err = p.consume(SEMICOLON, "Expect ';' after import")
+    if err != nil { return nil, err }
+
+    return NewImport(keyword, path, name), nil
+}
+
+// RULE classDecl: "class" IDENTIFIER ( "<" IDENTIFIER )? "{" function* "}"
+func (p *Parser) classDecl() (Stmt, error) {
+    name, err := p.consume(IDENTIFIER, "Expect class name")
+    if err != nil { return nil, err }
+
+    var superclass Expr = nil
+    if p.match(LESS) {
+        super, err := p.consume(IDENTIFIER, "Expect superclass name")
+        if err != nil { return nil, err }
+        superclass = NewVariable(super)
+    }
+
+    _, err = p.consume(LEFT_BRACE, "Expect '{' before class body")
+    if err != nil { return nil, err }
+
+    methods := make([]Function, 0)
+    getters := make([]Function, 0)
+    statics := make([]Function, 0)
+    for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+        // "static" is only special here so it stays usable as a method name
+        if p.check(IDENTIFIER) && p.peek().Lexeme == "static" && p.checkNext(IDENTIFIER) {
+            p.advance()
+            method, err := p.function("static method")
+            if err != nil { return nil, err }
+            statics = append(statics, method)
+        } else if p.check(IDENTIFIER) && p.checkNext(LEFT_BRACE) {
+            getter, err := p.getter()
+            if err != nil { return nil, err }
+            getters = append(getters, getter)
+        } else {
+            method, err := p.function("method")
+            if err != nil { return nil, err }
+            methods = append(methods, method)
+        }
+    }
+
+    _, err = p.consume(RIGHT_BRACE, "Expect '}' after class body")
     if err != nil { return nil, err }
+
+    return NewClass(name, superclass, methods, getters, statics), nil
+}
+
+// RULE getter: IDENTIFIER block
+// a method without a parameter list, run whenever the property is read
+func (p *Parser) getter() (Function, error) {
+    name := p.advance()
+    p.advance()
+
+    body, err := p.functionBlock()
+    if err != nil { return Function{}, err }
+    return NewFunction(name, make([]Token, 0), make([]Expr, 0), Token{}, body), nil
+}
+
+// RULE: function: IDENTIFIER functionBody
+func (p *Parser) function(kind string) (Function, error) {
+    name, err := p.consume(IDENTIFIER, "Expect " + kind + " name")
+    if err != nil { return Function{}, err }
     _, err = p.consume(LEFT_PAREN, "Expect '(' after " + kind + " name")
+    if err != nil { return Function{}, err }
+
+    return p.functionBody(name, kind)
+}
+
+// RULE: functionBody: "(" parameters? ")" block
+// RULE: parameters: IDENTIFIER ( "=" expression )? ( "," IDENTIFIER ( "=" expression )? )*
+//                   ( "," "..." IDENTIFIER )? | "..." IDENTIFIER
+// the opening "(" has already been consumed by the caller
+func (p *Parser) functionBody(name Token, kind string) (Function, error) {
     params := make([]Token, 0)
+    // one entry per parameter, nil when it has no default
+    defaults := make([]Expr, 0)
+    var rest Token
     if !p.check(RIGHT_PAREN) {
         for {
             if len(params) >= 255 {
                 reportErr(p.peek(), "Can't have more than 255 parameters")
             }
 
+            if p.match(ELLIPSIS) {
+                add, err := p.consume(IDENTIFIER, "Expect rest parameter name after '...'")
+                if err != nil { return Function{}, err }
+                rest = add
+
+                if !p.check(RIGHT_PAREN) {
+                    return Function{}, reportErr(p.peek(), "Rest parameter must be the last parameter")
+                }
+                break
+            }
+
             add, err := p.consume(IDENTIFIER, "Expect parameter name")
-            if err != nil { return nil, err }
+            if err != nil { return Function{}, err }
             params = append(params, add)
+
+            var def Expr = nil
+            if p.match(EQUAL) {
+                def, err = p.expression()
+                if err != nil { return Function{}, err }
+            } else if len(defaults) > 0 && defaults[len(defaults) - 1] != nil {
+                reportErr(add, "Parameters without defaults can't follow ones with defaults")
+            }
+            defaults = append(defaults, def)
+
             if !p.match(COMMA) {
                 break
             } 
         }
     }
-    _, err = p.consume(RIGHT_PAREN, "Expect ')' after parameters")
-    if err != nil { return nil, err }
+    _, err := p.consume(RIGHT_PAREN, "Expect ')' after parameters")
+    if err != nil { return Function{}, err }
 
     _, err = p.consume(LEFT_BRACE, "Expect '{' before " + kind + " body")
-    if err != nil { return nil, err }
+    if err != nil { return Function{}, err }
+
+    body, err := p.functionBlock()
+    if err != nil { return Function{}, err }
+    return NewFunction(name, params, defaults, rest, body), nil
+}
+
+// function to parse the block of a function body, its "{" already consumed
+func (p *Parser) functionBlock() ([]Stmt, error) {
+    // loops outside the function body can't be broken out of from inside it
+    enclosingLoopDepth, enclosingTryDepth := p.loopDepth, p.tryDepth
+    p.loopDepth, p.tryDepth = 0, 0
     body, err := p.block()
-    if err != nil { return nil, err }
-    return NewFunction(name, params, body), nil
+    p.loopDepth, p.tryDepth = enclosingLoopDepth, enclosingTryDepth
+    return body, err
 }
 
-// RULE statement: exprStmt | forStmt | ifStmt | printStmt | returnStmt | whileStmt | block
+// RULE statement: exprStmt | forStmt | ifStmt | printStmt | returnStmt | whileStmt
+//                 | breakStmt | continueStmt | throwStmt | tryStmt | matchStmt | block
 func (p *Parser) statement() (Stmt, error) {
+    if p.match(MATCH) {
+        return p.matchStmt()
+    }
+    if p.match(THROW) {
+        return p.throwStmt()
+    }
+    if p.match(TRY) {
+        return p.tryStmt()
+    }
+    if p.match(BREAK) {
+        return p.breakStmt()
+    }
+    if p.match(CONTINUE) {
+        return p.continueStmt()
+    }
     if p.match(FOR) {
         return p.forStmt()
     }
@@ -116,7 +280,9 @@ func (p *Parser) statement() (Stmt, error) {
     if p.match(WHILE) {
         return p.whileStmt()
     }
-    if p.match(LEFT_BRACE) {
+    // "{x, y} = m;" destructures rather than opening a block
+    if p.check(LEFT_BRACE) && !p.isMapLiteral() && !p.isDestructuring() {
+        p.advance()
         val, err := p.block()
         if err != nil { return nil, err }
         return NewBlock(val), nil
@@ -127,10 +293,16 @@ func (p *Parser) statement() (Stmt, error) {
 
 // RULE forStmt: "for" "(" ( varDecl | exprStmt | ";" )
 //               expression? ";" expression? ")" statement
+//               | forInStmt
 func (p *Parser) forStmt() (Stmt, error) {
     _, err := p.consume(LEFT_PAREN, "Expect '(' after for")
     if err != nil { return nil, err }
 
+    // "in" is only special here so it stays usable as a variable name
+    if p.check(VAR) && p.checkNext(IDENTIFIER) && p.tokens[p.curr + 2].Lexeme == "in" {
+        return p.forInStmt()
+    }
+
     var initializer Stmt
     if p.match(SEMICOLON) {
         initializer = nil
@@ -160,17 +332,16 @@ func (p *Parser) forStmt() (Stmt, error) {
     _, err = p.consume(RIGHT_PAREN, "Expect ')' after for clauses")
     if err != nil { return nil, err }
 
+    p.loopDepth++
     body, err := p.statement()
+    p.loopDepth--
     if err != nil { return nil, err }
 
-    if increment != nil {
-        body = NewBlock( []Stmt{body, NewStmtExpression(increment)} )
-    }
-
+    // the increment is kept out of the body so "continue" still runs it
     if condition == nil {
         condition = NewLiteral(true)
     }
-    body = NewWhile(condition, body)
+    body = NewWhile(condition, body, increment)
 
     if initializer != nil {
         body = NewBlock( []Stmt{initializer, body} )
@@ -179,6 +350,26 @@ func (p *Parser) forStmt() (Stmt, error) {
     return body, nil
 }
 
+// RULE forInStmt: "for" "(" "var" IDENTIFIER "in" expression ")" statement
+func (p *Parser) forInStmt() (Stmt, error) {
+    p.advance()
+    name := p.advance()
+    keyword := p.advance()
+
+    iterable, err := p.expression()
+    if err != nil { return nil, err }
+
+    _, err = p.consume(RIGHT_PAREN, "Expect ')' after for-in clause")
+    if err != nil { return nil, err }
+
+    p.loopDepth++
+    body, err := p.statement()
+    p.loopDepth--
+    if err != nil { return nil, err }
+
+    return NewForIn(name, keyword, iterable, body), nil
+}
+
 // RULE whileStmt: "while" "(" expression ")" statement
 func (p *Parser) whileStmt() (Stmt, error) {
     _, err := p.consume(LEFT_PAREN, "Expect '(' after while")
@@ -190,10 +381,181 @@ func (p *Parser) whileStmt() (Stmt, error) {
     _, err = p.consume(RIGHT_PAREN, "Expect ')' after condition")
     if err != nil { return nil, err }
 
+    p.loopDepth++
     body, err := p.statement()
+    p.loopDepth--
+    if err != nil { return nil, err }
+
+    return NewWhile(condition, body, nil), nil
+}
+
+// RULE breakStmt: "break" ";"
+func (p *Parser) breakStmt() (Stmt, error) {
+    keyword := p.previous()
+    if p.loopDepth == 0 {
+        reportErr(keyword, "Can't use 'break' outside of a loop")
+    }
+
+    _, err := p.consume(SEMICOLON, "Expect ';' after 'break'")
     if err != nil { return nil, err }
 
-    return NewWhile(condition, body), nil
+    return NewBreak(keyword), nil
+}
+
+// RULE continueStmt: "continue" ";"
+func (p *Parser) continueStmt() (Stmt, error) {
+    keyword := p.previous()
+    if p.loopDepth == 0 {
+        reportErr(keyword, "Can't use 'continue' outside of a loop")
+    }
+
+    _, err := p.consume(SEMICOLON, "Expect ';' after 'continue'")
+    if err != nil { return nil, err }
+
+    return NewContinue(keyword), nil
+}
+
+// RULE throwStmt: "throw" expression ";"
+func (p *Parser) throwStmt() (Stmt, error) {
+    keyword := p.previous()
+    value, err := p.expression()
+    if err != nil { return nil, err }
+
+    _, err = p.consume(SEMICOLON, "Expect ';' after thrown value")
+    if err != nil { return nil, err }
+
+    return NewThrow(keyword, value), nil
+}
+
+// RULE tryStmt: "try" block ( "catch" "(" IDENTIFIER ")" block )? ( "finally" block )?
+// at least one of the catch or finally clauses must be present
+func (p *Parser) tryStmt() (Stmt, error) {
+    keyword := p.previous()
+    _, err := p.consume(LEFT_BRACE, "Expect '{' after 'try'")
+    if err != nil { return nil, err }
+
+    p.tryDepth++
+    statements, err := p.block()
+    p.tryDepth--
+    if err != nil { return nil, err }
+    body := NewBlock(statements)
+
+    var catchName Token
+    var catchBody Stmt = nil
+    if p.match(CATCH) {
+        _, err = p.consume(LEFT_PAREN, "Expect '(' after 'catch'")
+        if err != nil { return nil, err }
+
+        catchName, err = p.consume(IDENTIFIER, "Expect error variable name")
+        if err != nil { return nil, err }
+
+        _, err = p.consume(RIGHT_PAREN, "Expect ')' after error variable")
+        if err != nil { return nil, err }
+
+        _, err = p.consume(LEFT_BRACE, "Expect '{' before catch body")
+        if err != nil { return nil, err }
+
+        p.tryDepth++
+        statements, err = p.block()
+        p.tryDepth--
+        if err != nil { return nil, err }
+        catchBody = NewBlock(statements)
+    }
+
+    var finallyBody Stmt = nil
+    if p.match(FINALLY) {
+        _, err = p.consume(LEFT_BRACE, "Expect '{' after 'finally'")
+        if err != nil { return nil, err }
+
+        statements, err = p.block()
+        if err != nil { return nil, err }
+        finallyBody = NewBlock(statements)
+    }
+
+    if catchBody == nil && finallyBody == nil {
+        return nil, reportErr(keyword, "Expect 'catch' or 'finally' after try block")
+    }
+
+    return NewTry(body, catchName, catchBody, finallyBody), nil
+}
+
+// RULE matchStmt: "match" "(" expression ")" "{" matchCase* ( "default" "=>" statement )? "}"
+// RULE matchCase: "case" pattern ( "," pattern )* ( "if" expression )? "=>" statement
+func (p *Parser) matchStmt() (Stmt, error) {
+    keyword := p.previous()
+    _, err := p.consume(LEFT_PAREN, "Expect '(' after 'match'")
+    if err != nil { return nil, err }
+
+    subject, err := p.expression()
+    if err != nil { return nil, err }
+
+    _, err = p.consume(RIGHT_PAREN, "Expect ')' after match value")
+    if err != nil { return nil, err }
+
+    _, err = p.consume(LEFT_BRACE, "Expect '{' before match cases")
+    if err != nil { return nil, err }
+
+    patterns := make([][]Expr, 0)
+    guards := make([]Expr, 0)
+    bodies := make([]Stmt, 0)
+    for p.match(CASE) {
+        casePatterns := make([]Expr, 0)
+        for {
+            pattern, err := p.pattern()
+            if err != nil { return nil, err }
+            casePatterns = append(casePatterns, pattern)
+            if !p.match(COMMA) {
+                break
+            }
+        }
+
+        var guard Expr = nil
+        if p.match(IF) {
+            guard, err = p.expression()
+            if err != nil { return nil, err }
+        }
+
+        _, err = p.consume(ARROW, "Expect '=>' after case pattern")
+        if err != nil { return nil, err }
+
+        body, err := p.statement()
+        if err != nil { return nil, err }
+
+        patterns = append(patterns, casePatterns)
+        guards = append(guards, guard)
+        bodies = append(bodies, body)
+    }
+
+    var defaultBody Stmt = nil
+    if p.match(DEFAULT) {
+        _, err = p.consume(ARROW, "Expect '=>' after 'default'")
+        if err != nil { return nil, err }
+
+        defaultBody, err = p.statement()
+        if err != nil { return nil, err }
+    }
+
+    _, err = p.consume(RIGHT_BRACE, "Expect '}' after match cases")
+    if err != nil { return nil, err }
+
+    return NewMatch(keyword, subject, patterns, guards, bodies, defaultBody), nil
+}
+
+// RULE pattern: "-"? NUMBER | STRING | "true" | "false" | "nil"
+func (p *Parser) pattern() (Expr, error) {
+    if p.match(MINUS) {
+        operator := p.previous()
+        number, err := p.consume(NUMBER, "Expect number after '-' in pattern")
+        if err != nil { return nil, err }
+
+        return NewUnary(operator, NewLiteral(number.Literal)), nil
+    }
+
+    if p.check(NUMBER) || p.check(STRING) || p.check(TRUE) || p.check(FALSE) || p.check(NIL) {
+        return p.primary()
+    }
+
+    return nil, reportErr(p.peek(), "Expect literal pattern")
 }
 
 // RULE ifStmt: "if" "(" expression ")" statement ( "else" statement )?
@@ -260,11 +622,20 @@ func (p *Parser) returnStmt() (Stmt, error) {
 
     _, err := p.consume(SEMICOLON, "Expect ';' after return value")
     if err != nil { return nil, err }
-    return NewReturn(keyword, val), nil
+
+    // a returned call is the last thing the function does so it can reuse
+    // the frame, unless a catch or finally still has to run after it
+    _, isCall := val.(*Call)
+    return NewReturn(keyword, val, isCall && p.tryDepth == 0), nil
 }
 
 // RULE "var" IDENTIFIER ( "=" expression )? ";"
+//       | "var" destructuring "=" expression ";"
 func (p *Parser) varDecl() (Stmt, error) {
+    if p.check(LEFT_BRACKET) || p.check(LEFT_BRACE) {
+        return p.varPattern()
+    }
+
     name, err := p.consume(IDENTIFIER, "Expect variable name")
     if err != nil { return nil, err }
 
@@ -280,6 +651,80 @@ func (p *Parser) varDecl() (Stmt, error) {
     return NewVar(name, initializer), nil
 }
 
+// function to finish a var declaration that destructures its initializer
+func (p *Parser) varPattern() (Stmt, error) {
+    opening, names, rest, err := p.destructuring()
+    if err != nil { return nil, err }
+
+    _, err = p.consume(EQUAL, "Expect '=' after destructuring pattern")
+    if err != nil { return nil, err }
+
+    initializer, err := p.expression()
+    if err != nil { return nil, err }
+
+    _, err = p.consume(SEMICOLON, "Expect ';' after variable declaration")
+    if err != nil { return nil, err }
+
+    return NewVarPattern(opening, names, rest, initializer), nil
+}
+
+// RULE destructuring: "[" ( IDENTIFIER ( "," IDENTIFIER )* ( "," "..." IDENTIFIER )?
+//                     | "..." IDENTIFIER )? "]"
+//                     | "{" ( IDENTIFIER ( "," IDENTIFIER )* )? "}"
+// list patterns bind elements by position and map patterns bind keys by name
+func (p *Parser) destructuring() (Token, []Token, Token, error) {
+    opening := p.advance()
+    closing, kind := RIGHT_BRACKET, "]"
+    if opening.Type == LEFT_BRACE {
+        closing, kind = RIGHT_BRACE, "}"
+    }
+
+    names := make([]Token, 0)
+    var rest Token
+    if !p.check(closing) {
+        for {
+            if opening.Type == LEFT_BRACKET && p.match(ELLIPSIS) {
+                add, err := p.consume(IDENTIFIER, "Expect rest variable name after '...'")
+                if err != nil { return opening, nil, rest, err }
+                rest = add
+
+                if !p.check(closing) {
+                    return opening, nil, rest, reportErr(p.peek(), "Rest variable must be the last in a pattern")
+                }
+                break
+            }
+
+            name, err := p.consume(IDENTIFIER, "Expect variable name in pattern")
+            if err != nil { return opening, nil, rest, err }
+            names = append(names, name)
+
+            if !p.match(COMMA) {
+                break
+            }
+        }
+    }
+
+    _, err := p.consume(closing, "Expect '" + kind + "' after pattern")
+    return opening, names, rest, err
+}
+
+// RULE constDecl: "const" IDENTIFIER "=" expression ";"
+func (p *Parser) constDecl() (Stmt, error) {
+    name, err := p.consume(IDENTIFIER, "Expect constant name")
+    if err != nil { return nil, err }
+
+    _, err = p.consume(EQUAL, "Expect '=' after constant name")
+    if err != nil { return nil, err }
+
+    initializer, err := p.expression()
+    if err != nil { return nil, err }
+
+    _, err = p.consume(SEMICOLON, "Expect ';' after constant declaration")
+    if err != nil { return nil, err }
+
+    return NewConst(name, initializer), nil
+}
+
 // RULE exprStmt: expression ";"
 func (p *Parser) exprStmt() (Stmt, error) {
     expr, err := p.expression()
@@ -296,18 +741,63 @@ func (p *Parser) expression() (Expr, error) {
     return p.assignment()
 }
 
-// RULE assignment: IDENTIFIER "=" assignment | logic_or
+// RULE assignment: ( call "." )? IDENTIFIER ( "=" | "+=" | "-=" | "*=" | "/=" ) assignment
+//                  | call "[" expression "]" ( "=" | "+=" | "-=" | "*=" | "/=" ) assignment
+//                  | destructuring "=" assignment
+//                  | conditional
 func (p *Parser) assignment() (Expr, error) {
-    expr, err := p.or()
+    if p.isDestructuring() {
+        opening, names, rest, err := p.destructuring()
+        if err != nil { return nil, err }
+        p.advance()
+
+        value, err := p.assignment()
+        if err != nil { return nil, err }
+
+        targets := make([]Expr, len(names))
+        for k, name := range names {
+            targets[k] = NewVariable(name)
+        }
+        var restTarget Expr = nil
+        if rest.Lexeme != "" {
+            restTarget = NewVariable(rest)
+        }
+        return NewAssignPattern(opening, targets, restTarget, value), nil
+    }
+
+    expr, err := p.conditional()
     if err != nil { return nil, err }
 
+    if p.match(PLUS_EQUAL, MINUS_EQUAL, STAR_EQUAL, SLASH_EQUAL) {
+        operator := p.previous()
+        value, err := p.assignment()
+        if err != nil { return nil, err }
+
+        if !isAssignable(expr) {
+            return nil, reportErr(operator, "Invalid assignment target")
+        }
+        return NewUpdate(expr, operator, value, false), nil
+    }
+
     if p.match(EQUAL) {
         switch expr.(type) {
-        case Variable:
+        case *Variable:
+            value, err := p.assignment()
+            if err != nil { return nil, err }
+
+            return NewAssign(expr.(*Variable).Name, value), nil
+        case *Get:
             value, err := p.assignment()
             if err != nil { return nil, err }
 
-            return NewAssign(expr.(Variable).Name, value), nil
+            get := expr.(*Get)
+            return NewSet(get.Object, get.Name, value), nil
+        case *Subscript:
+            value, err := p.assignment()
+            if err != nil { return nil, err }
+
+            subscript := expr.(*Subscript)
+            return NewSetSubscript(subscript.Object, subscript.Bracket, subscript.Index, value), nil
         default:
             equals := p.previous()
             _, err := p.assignment()
@@ -320,6 +810,43 @@ func (p *Parser) assignment() (Expr, error) {
     return expr, nil 
 }
 
+// RULE conditional: coalesce ( "?" expression ":" conditional )?
+func (p *Parser) conditional() (Expr, error) {
+    expr, err := p.coalesce()
+    if err != nil { return nil, err }
+
+    if p.match(QUESTION) {
+        thenBranch, err := p.expression()
+        if err != nil { return nil, err }
+
+        _, err = p.consume(COLON, "Expect ':' after then branch of conditional expression")
+        if err != nil { return nil, err }
+
+        elseBranch, err := p.conditional()
+        if err != nil { return nil, err }
+
+        expr = NewConditional(expr, thenBranch, elseBranch)
+    }
+
+    return expr, nil
+}
+
+// RULE coalesce: logic_or ( "??" logic_or )*
+func (p *Parser) coalesce() (Expr, error) {
+    expr, err := p.or()
+    if err != nil { return nil, err }
+
+    for p.match(QUESTION_QUESTION) {
+        operator := p.previous()
+        right, err := p.or()
+        if err != nil { return nil, err }
+
+        expr = NewLogical(expr, operator, right)
+    }
+
+    return expr, nil
+}
+
 // RULE logic_or: logic_and ( "or" logic_and )*
 func (p *Parser) or() (Expr, error) {
     expr, err := p.and()
@@ -341,7 +868,7 @@ func (p *Parser) and() (Expr, error) {
     expr, err := p.equality()
     if err != nil { return nil, err }
 
-    for p.match(OR) {
+    for p.match(AND) {
         operator := p.previous()
         right, err := p.equality()
         if err != nil { return nil, err }
@@ -398,12 +925,12 @@ func (p *Parser) term() (Expr, error) {
     return expr, nil
 }
 
-// RULE factor: unary ( ( "/" | "*" ) unary )*
+// RULE factor: unary ( ( "/" | "*" | "%" | "~/" ) unary )*
 func (p *Parser) factor() (Expr, error) {
     expr, err := p.unary()
     if err != nil { return nil, err }
 
-    for p.match(SLASH, STAR) {
+    for p.match(SLASH, STAR, PERCENT, TILDE_SLASH) {
         operator := p.previous()
         right, err := p.unary()
         if err != nil { return nil, err }
@@ -413,7 +940,7 @@ func (p *Parser) factor() (Expr, error) {
     return expr, nil
 }
 
-// RULE unary: ( "!" | "-" ) unary | call
+// RULE unary: ( "!" | "-" ) unary | exponent
 func (p *Parser) unary() (Expr, error) {
     if p.match(BANG, MINUS) {
         operator := p.previous()
@@ -423,26 +950,115 @@ func (p *Parser) unary() (Expr, error) {
         return NewUnary(operator, right), err
     }
 
-    return p.call()
+    return p.exponent()
+}
+
+// RULE exponent: prefix ( "**" unary )?
+// the right operand recurses through unary so "**" is right-associative
+// and binds tighter than a unary minus on its left: -2 ** 2 == -4
+func (p *Parser) exponent() (Expr, error) {
+    expr, err := p.prefix()
+    if err != nil { return nil, err }
+
+    if p.match(STAR_STAR) {
+        operator := p.previous()
+        right, err := p.unary()
+        if err != nil { return nil, err }
+
+        expr = NewBinary(expr, operator, right)
+    }
+
+    return expr, nil
+}
+
+// RULE prefix: ( "++" | "--" ) call | postfix
+// the operand is a call so ++a ** 2 increments a before raising it
+func (p *Parser) prefix() (Expr, error) {
+    if p.match(PLUS_PLUS, MINUS_MINUS) {
+        operator := p.previous()
+        operand, err := p.call()
+        if err != nil { return nil, err }
+
+        if !isAssignable(operand) {
+            return nil, reportErr(operator, "Invalid increment target")
+        }
+        return NewUpdate(operand, operator, NewLiteral(int64(1)), false), nil
+    }
+
+    return p.postfix()
+}
+
+// RULE postfix: call ( "++" | "--" )?
+func (p *Parser) postfix() (Expr, error) {
+    expr, err := p.call()
+    if err != nil { return nil, err }
+
+    if p.match(PLUS_PLUS, MINUS_MINUS) {
+        operator := p.previous()
+        if !isAssignable(expr) {
+            return nil, reportErr(operator, "Invalid increment target")
+        }
+        return NewUpdate(expr, operator, NewLiteral(int64(1)), true), nil
+    }
+
+    return expr, nil
 }
 
-// RULE call: primary ( "(" arguments? ")" )*
+// RULE call: primary ( "(" arguments? ")" | "." IDENTIFIER | "[" expression "]"
+//                    | "?." ( IDENTIFIER | "(" arguments? ")" | "[" expression "]" ) )*
 func (p *Parser) call() (Expr, error) {
     expr, err := p.primary()
     if err != nil { return nil, err }
 
+    // a chain with any "?." is wrapped whole so nil skips all of its rest
+    optional := false
     for {
         if p.match(LEFT_PAREN) {
             expr, err = p.finishCall(expr)
             if err != nil { return nil, err }
+        } else if p.match(DOT) {
+            name, err := p.consume(IDENTIFIER, "Expect property name after '.'")
+            if err != nil { return nil, err }
+            expr = NewGet(expr, name)
+        } else if p.match(LEFT_BRACKET) {
+            expr, err = p.finishSubscript(expr)
+            if err != nil { return nil, err }
+        } else if p.match(QUESTION_DOT) {
+            optional = true
+            expr = NewOptional(expr)
+            if p.match(LEFT_PAREN) {
+                expr, err = p.finishCall(expr)
+                if err != nil { return nil, err }
+            } else if p.match(LEFT_BRACKET) {
+                expr, err = p.finishSubscript(expr)
+                if err != nil { return nil, err }
+            } else {
+                name, err := p.consume(IDENTIFIER, "Expect property name, '(' or '[' after '?.'")
+                if err != nil { return nil, err }
+                expr = NewGet(expr, name)
+            }
         } else {
             break
         }
     }
 
+    if optional {
+        expr = NewOptionalChain(expr)
+    }
     return expr, nil
 }
 
+// function to finish parsing object[index], the "[" already consumed
+func (p *Parser) finishSubscript(object Expr) (Expr, error) {
+    bracket := p.previous()
+    index, err := p.expression()
+    if err != nil { return nil, err }
+
+    _, err = p.consume(RIGHT_BRACKET, "Expect ']' after index")
+    if err != nil { return nil, err }
+    return NewSubscript(object, bracket, index), nil
+}
+
 func (p *Parser) finishCall(callee Expr) (Expr, error) {
     args := make([]Expr, 0)
     if !p.check(RIGHT_PAREN) {
@@ -466,7 +1082,11 @@ func (p *Parser) finishCall(callee Expr) (Expr, error) {
     return NewCall(callee, paren, args), nil
 }
 
-// RULE primary: NUMBER | STRING | "true" | "false" | "nil" | "(" expression ")" | IDENTIFIER
+// RULE primary: NUMBER | STRING | "true" | "false" | "nil" | "this"
+//               | "(" expression ")" | IDENTIFIER | "super" "." IDENTIFIER
+//               | "fun" functionBody | "[" ( expression ( "," expression )* )? "]"
+//               | "{" ( expression ":" expression ( "," expression ":" expression )* )? "}"
+//               | ( INTERPOLATION expression )+ STRING
 func (p *Parser) primary() (Expr, error) {
     switch {
     case p.match(FALSE):
@@ -477,8 +1097,94 @@ func (p *Parser) primary() (Expr, error) {
         return NewLiteral(nil), nil
     case p.match(NUMBER, STRING):
         return NewLiteral(p.previous().Literal), nil
+    case p.match(SUPER):
+        keyword := p.previous()
+        _, err := p.consume(DOT, "Expect '.' after 'super'")
+        if err != nil { return nil, err }
+
+        method, err := p.consume(IDENTIFIER, "Expect superclass method name")
+        if err != nil { return nil, err }
+
+        return NewSuper(keyword, method), nil
+    case p.match(INTERPOLATION):
+        parts := []Expr{ NewLiteral(p.previous().Literal) }
+        for {
+            expr, err := p.expression()
+            if err != nil { return nil, err }
+            parts = append(parts, expr)
+
+            if p.match(INTERPOLATION) {
+                parts = append(parts, NewLiteral(p.previous().Literal))
+                continue
+            }
+
+            end, err := p.consume(STRING, "Expect '}' after interpolated expression")
+            if err != nil { return nil, err }
+            parts = append(parts, NewLiteral(end.Literal))
+            break
+        }
+
+        return NewInterpolation(parts), nil
+    case p.match(THIS):
+        return NewThis(p.previous()), nil
+    case p.match(FUN):
+        keyword := p.previous()
+        _, err := p.consume(LEFT_PAREN, "Expect '(' after 'fun'")
+        if err != nil { return nil, err }
+
+        // anonymous functions are given a name token with an empty lexeme
+        name := NewToken(IDENTIFIER, "", nil, keyword.Line)
+        declaration, err := p.functionBody(name, "function")
+        if err != nil { return nil, err }
+
+        return NewLambda(keyword, declaration), nil
     case p.match(IDENTIFIER):
         return NewVariable(p.previous()), nil
+    case p.match(LEFT_BRACKET):
+        bracket := p.previous()
+        elements := make([]Expr, 0)
+        if !p.check(RIGHT_BRACKET) {
+            for {
+                element, err := p.expression()
+                if err != nil { return nil, err }
+                elements = append(elements, element)
+                if !p.match(COMMA) {
+                    break
+                }
+            }
+        }
+
+        _, err := p.consume(RIGHT_BRACKET, "Expect ']' after list elements")
+        if err != nil { return nil, err }
+
+        return NewList(bracket, elements), nil
+    case p.match(LEFT_BRACE):
+        brace := p.previous()
+        keys := make([]Expr, 0)
+        values := make([]Expr, 0)
+        if !p.check(RIGHT_BRACE) {
+            for {
+                key, err := p.expression()
+                if err != nil { return nil, err }
+
+                _, err = p.consume(COLON, "Expect ':' after map key")
+                if err != nil { return nil, err }
+
+                value, err := p.expression()
+                if err != nil { return nil, err }
+
+                keys = append(keys, key)
+                values = append(values, value)
+                if !p.match(COMMA) {
+                    break
+                }
+            }
+        }
+
+        _, err := p.consume(RIGHT_BRACE, "Expect '}' after map entries")
+        if err != nil { return nil, err }
+
+        return NewMap(brace, keys, values), nil
     case p.match(LEFT_PAREN):
         expr, err := p.expression()
         if err != nil { return nil, err }
@@ -505,6 +1211,50 @@ func (p *Parser) match(types ...TokenType) bool {
     return false
 }
 
+// function to check if the token after the current one matches the given type
+func (p *Parser) checkNext(ttype TokenType) bool {
+    if p.isAtEnd() || p.tokens[p.curr + 1].Type == EOF {
+        return false
+    }
+
+    return p.tokens[p.curr + 1].Type == ttype
+}
+
+// function to check if the "{" at the current token opens a map literal
+// rather than a block, i.e. it is followed by a single token key and ":"
+func (p *Parser) isMapLiteral() bool {
+    if p.curr + 2 >= len(p.tokens) {
+        return false
+    }
+
+    return p.tokens[p.curr + 2].Type == COLON
+}
+
+// function to check if the "[" or "{" at the current token opens a
+// destructuring pattern, which is when its matching close is followed by "="
+func (p *Parser) isDestructuring() bool {
+    if !p.check(LEFT_BRACKET) && !p.check(LEFT_BRACE) {
+        return false
+    }
+
+    depth := 0
+    for k := p.curr; k < len(p.tokens); k++ {
+        switch p.tokens[k].Type {
+        case LEFT_BRACKET, LEFT_BRACE, LEFT_PAREN:
+            depth++
+        case RIGHT_BRACKET, RIGHT_BRACE, RIGHT_PAREN:
+            depth--
+            if depth == 0 {
+                return p.tokens[k + 1].Type == EQUAL
+            }
+        case EOF:
+            return false
+        }
+    }
+
+    return false
+}
+
 // function to consume current token if the type matches else return an error
 func (p *Parser) consume(ttype TokenType, msg string) (Token, error) {
     if p.check(ttype) {
@@ -550,6 +1300,16 @@ func (p *Parser) previous() Token {
     return p.tokens[p.curr - 1]
 }
 
+// function to check if an expression can be the target of an assignment
+func isAssignable(expr Expr) bool {
+    switch expr.(type) {
+    case *Variable, *Get, *Subscript:
+        return true
+    }
+
+    return false
+}
+
 // return error
 func reportErr(token Token, msg string) error {
     TokenError(token, msg)
@@ -572,15 +1332,25 @@ func (p *Parser) synchronize() {
             fallthrough
         case VAR:
             fallthrough
+        case CONST:
+            fallthrough
         case FOR:
             fallthrough
         case IF:
             fallthrough
+        case IMPORT:
+            fallthrough
+        case MATCH:
+            fallthrough
         case WHILE:
             fallthrough
         case PRINT:
             fallthrough
         case RETURN:
+            fallthrough
+        case THROW:
+            fallthrough
+        case TRY:
             return
         }
 
@@ -0,0 +1,550 @@
+package resolver
+
+import (
+    . "glox/util"
+    . "glox/token"
+    . "glox/ast"
+    "glox/interpreter"
+)
+
+// Enum to track what kind of function body is being resolved
+type FunctionType int
+
+const (
+    NO_FUNCTION FunctionType = iota
+    FUNCTION
+    INITIALIZER
+    METHOD
+)
+
+// Enum to track what kind of class body is being resolved
+type ClassType int
+
+const (
+    NO_CLASS ClassType = iota
+    IN_CLASS
+    IN_SUBCLASS
+    IN_STATIC
+)
+
+type Resolver struct {
+    interpreter interpreter.Interpreter
+    // each scope maps a variable name to whether its initializer has finished
+    scopes []map[string]bool
+    // names declared with "const", one map per scope plus the module globals
+    constants []map[string]bool
+    globalConstants map[string]bool
+    currentFunction FunctionType
+    currentClass ClassType
+}
+
+// Resolver "constructor"
+func NewResolver(i interpreter.Interpreter) *Resolver {
+    return &Resolver{interpreter: i, globalConstants: make(map[string]bool)}
+}
+
+// function to resolve every variable reference in a list of statements
+// errors are reported through TokenError so util.HadError is set on failure
+func (r *Resolver) Resolve(statements []Stmt) {
+    for _, statement := range statements {
+        r.resolveStmt(statement)
+    }
+}
+
+// VISITOR FUNCTIONS
+
+func (r *Resolver) VisitBlock(stmt Block) (Object, error) {
+    r.beginScope()
+    r.Resolve(stmt.Statements)
+    r.endScope()
+    return nil, nil
+}
+
+func (r *Resolver) VisitClass(stmt Class) (Object, error) {
+    enclosingClass := r.currentClass
+    r.currentClass = IN_CLASS
+
+    r.declare(stmt.Name)
+    r.define(stmt.Name)
+
+    if stmt.Superclass != nil {
+        superclass := stmt.Superclass.(*Variable)
+        if superclass.Name.Lexeme == stmt.Name.Lexeme {
+            TokenError(superclass.Name, "A class can't inherit from itself")
+        }
+
+        r.resolveExpr(superclass)
+    }
+
+    // static methods are called on the class itself so see neither "this" nor "super"
+    r.currentClass = IN_STATIC
+    for _, method := range stmt.StaticMethods {
+        r.resolveFunction(method, METHOD)
+    }
+    r.currentClass = IN_CLASS
+
+    if stmt.Superclass != nil {
+        r.currentClass = IN_SUBCLASS
+        r.beginScope()
+        r.peekScope()["super"] = true
+    }
+
+    r.beginScope()
+    r.peekScope()["this"] = true
+
+    for _, method := range stmt.Methods {
+        declaration := METHOD
+        if method.Name.Lexeme == "init" {
+            declaration = INITIALIZER
+        }
+        r.resolveFunction(method, declaration)
+    }
+    for _, getter := range stmt.Getters {
+        r.resolveFunction(getter, METHOD)
+    }
+
+    r.endScope()
+
+    if stmt.Superclass != nil {
+        r.endScope()
+    }
+
+    r.currentClass = enclosingClass
+    return nil, nil
+}
+
+func (r *Resolver) VisitStmtExpression(stmt StmtExpression) (Object, error) {
+    r.resolveExpr(stmt.Expression)
+    return nil, nil
+}
+
+func (r *Resolver) VisitForIn(stmt ForIn) (Object, error) {
+    r.resolveExpr(stmt.Iterable)
+
+    // the loop variable lives in its own scope, fresh on every iteration
+    r.beginScope()
+    r.declare(stmt.Name)
+    r.define(stmt.Name)
+    r.resolveStmt(stmt.Body)
+    r.endScope()
+    return nil, nil
+}
+
+func (r *Resolver) VisitFunction(stmt Function) (Object, error) {
+    // define eagerly so the function can refer to itself recursively
+    r.declare(stmt.Name)
+    r.define(stmt.Name)
+
+    r.resolveFunction(stmt, FUNCTION)
+    return nil, nil
+}
+
+func (r *Resolver) VisitIf(stmt If) (Object, error) {
+    r.resolveExpr(stmt.Condition)
+    r.resolveStmt(stmt.ThenBranch)
+    if stmt.ElseBranch != nil {
+        r.resolveStmt(stmt.ElseBranch)
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitImport(stmt Import) (Object, error) {
+    // relative paths are resolved against the module running its top level
+    if len(r.scopes) > 0 || r.currentFunction != NO_FUNCTION {
+        TokenError(stmt.Keyword, "Can only import at the top level of a module")
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitMatch(stmt Match) (Object, error) {
+    r.resolveExpr(stmt.Subject)
+    for k := range stmt.Bodies {
+        for _, pattern := range stmt.Patterns[k] {
+            r.resolveExpr(pattern)
+        }
+        if stmt.Guards[k] != nil {
+            r.resolveExpr(stmt.Guards[k])
+        }
+        r.resolveStmt(stmt.Bodies[k])
+    }
+
+    if stmt.Default != nil {
+        r.resolveStmt(stmt.Default)
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitPrint(stmt Print) (Object, error) {
+    r.resolveExpr(stmt.Expression)
+    return nil, nil
+}
+
+func (r *Resolver) VisitReturn(stmt Return) (Object, error) {
+    if r.currentFunction == NO_FUNCTION {
+        TokenError(stmt.Keyword, "Can't return from top-level code")
+    }
+
+    if stmt.Value != nil {
+        if r.currentFunction == INITIALIZER {
+            TokenError(stmt.Keyword, "Can't return a value from an initializer")
+        }
+        r.resolveExpr(stmt.Value)
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitThrow(stmt Throw) (Object, error) {
+    r.resolveExpr(stmt.Value)
+    return nil, nil
+}
+
+func (r *Resolver) VisitTry(stmt Try) (Object, error) {
+    r.resolveStmt(stmt.Body)
+
+    // the caught value shares a scope with the catch body
+    if stmt.CatchBody != nil {
+        r.beginScope()
+        r.declare(stmt.CatchName)
+        r.define(stmt.CatchName)
+        r.Resolve(stmt.CatchBody.(Block).Statements)
+        r.endScope()
+    }
+
+    if stmt.FinallyBody != nil {
+        r.resolveStmt(stmt.FinallyBody)
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitVar(stmt Var) (Object, error) {
+    r.declare(stmt.Name)
+    if stmt.Initializer != nil {
+        r.resolveExpr(stmt.Initializer)
+    }
+    r.define(stmt.Name)
+    return nil, nil
+}
+
+func (r *Resolver) VisitVarPattern(stmt VarPattern) (Object, error) {
+    for _, name := range stmt.Names {
+        r.declare(name)
+    }
+    if stmt.Rest.Lexeme != "" {
+        r.declare(stmt.Rest)
+    }
+
+    r.resolveExpr(stmt.Initializer)
+
+    for _, name := range stmt.Names {
+        r.define(name)
+    }
+    if stmt.Rest.Lexeme != "" {
+        r.define(stmt.Rest)
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitWhile(stmt While) (Object, error) {
+    r.resolveExpr(stmt.Condition)
+    r.resolveStmt(stmt.Body)
+    if stmt.Increment != nil {
+        r.resolveExpr(stmt.Increment)
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitBreak(stmt Break) (Object, error) {
+    return nil, nil
+}
+
+func (r *Resolver) VisitConst(stmt Const) (Object, error) {
+    r.declare(stmt.Name)
+    r.resolveExpr(stmt.Initializer)
+    r.define(stmt.Name)
+
+    if len(r.scopes) == 0 {
+        r.globalConstants[stmt.Name.Lexeme] = true
+    } else {
+        r.constants[len(r.constants) - 1][stmt.Name.Lexeme] = true
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitContinue(stmt Continue) (Object, error) {
+    return nil, nil
+}
+
+func (r *Resolver) VisitAssign(expr *Assign) (Object, error) {
+    r.checkNotConstant(expr.Name)
+    r.resolveExpr(expr.Value)
+    r.resolveLocal(expr, expr.Name)
+    return nil, nil
+}
+
+func (r *Resolver) VisitAssignPattern(expr *AssignPattern) (Object, error) {
+    r.resolveExpr(expr.Value)
+
+    for _, target := range expr.Targets {
+        r.resolveTarget(target.(*Variable))
+    }
+    if expr.Rest != nil {
+        r.resolveTarget(expr.Rest.(*Variable))
+    }
+    return nil, nil
+}
+
+// function to resolve a variable a destructuring assignment writes to
+func (r *Resolver) resolveTarget(variable *Variable) {
+    r.checkNotConstant(variable.Name)
+    r.resolveLocal(variable, variable.Name)
+}
+
+func (r *Resolver) VisitBinary(expr *Binary) (Object, error) {
+    r.resolveExpr(expr.Left)
+    r.resolveExpr(expr.Right)
+    return nil, nil
+}
+
+func (r *Resolver) VisitCall(expr *Call) (Object, error) {
+    r.resolveExpr(expr.Callee)
+    for _, arg := range expr.Arguments {
+        r.resolveExpr(arg)
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitConditional(expr *Conditional) (Object, error) {
+    r.resolveExpr(expr.Condition)
+    r.resolveExpr(expr.ThenBranch)
+    r.resolveExpr(expr.ElseBranch)
+    return nil, nil
+}
+
+func (r *Resolver) VisitGet(expr *Get) (Object, error) {
+    r.resolveExpr(expr.Object)
+    return nil, nil
+}
+
+func (r *Resolver) VisitGrouping(expr *Grouping) (Object, error) {
+    r.resolveExpr(expr.Expression)
+    return nil, nil
+}
+
+func (r *Resolver) VisitInterpolation(expr *Interpolation) (Object, error) {
+    for _, part := range expr.Parts {
+        r.resolveExpr(part)
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitLambda(expr *Lambda) (Object, error) {
+    r.resolveFunction(expr.Declaration, FUNCTION)
+    return nil, nil
+}
+
+func (r *Resolver) VisitList(expr *List) (Object, error) {
+    for _, element := range expr.Elements {
+        r.resolveExpr(element)
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitLiteral(expr *Literal) (Object, error) {
+    return nil, nil
+}
+
+func (r *Resolver) VisitLogical(expr *Logical) (Object, error) {
+    r.resolveExpr(expr.Left)
+    r.resolveExpr(expr.Right)
+    return nil, nil
+}
+
+func (r *Resolver) VisitMap(expr *Map) (Object, error) {
+    for k := range expr.Keys {
+        r.resolveExpr(expr.Keys[k])
+        r.resolveExpr(expr.Values[k])
+    }
+    return nil, nil
+}
+
+func (r *Resolver) VisitOptional(expr *Optional) (Object, error) {
+    r.resolveExpr(expr.Object)
+    return nil, nil
+}
+
+func (r *Resolver) VisitOptionalChain(expr *OptionalChain) (Object, error) {
+    r.resolveExpr(expr.Expression)
+    return nil, nil
+}
+
+func (r *Resolver) VisitSet(expr *Set) (Object, error) {
+    r.resolveExpr(expr.Value)
+    r.resolveExpr(expr.Object)
+    return nil, nil
+}
+
+func (r *Resolver) VisitSetSubscript(expr *SetSubscript) (Object, error) {
+    r.resolveExpr(expr.Value)
+    r.resolveExpr(expr.Object)
+    r.resolveExpr(expr.Index)
+    return nil, nil
+}
+
+func (r *Resolver) VisitSubscript(expr *Subscript) (Object, error) {
+    r.resolveExpr(expr.Object)
+    r.resolveExpr(expr.Index)
+    return nil, nil
+}
+
+func (r *Resolver) VisitSuper(expr *Super) (Object, error) {
+    if r.currentClass == NO_CLASS {
+        TokenError(expr.Keyword, "Can't use 'super' outside of a class")
+    } else if r.currentClass == IN_STATIC {
+        TokenError(expr.Keyword, "Can't use 'super' in a static method")
+    } else if r.currentClass != IN_SUBCLASS {
+        TokenError(expr.Keyword, "Can't use 'super' in a class with no superclass")
+    }
+
+    r.resolveLocal(expr, expr.Keyword)
+    return nil, nil
+}
+
+func (r *Resolver) VisitThis(expr *This) (Object, error) {
+    if r.currentClass == NO_CLASS {
+        TokenError(expr.Keyword, "Can't use 'this' outside of a class")
+        return nil, nil
+    }
+    if r.currentClass == IN_STATIC {
+        TokenError(expr.Keyword, "Can't use 'this' in a static method")
+        return nil, nil
+    }
+
+    r.resolveLocal(expr, expr.Keyword)
+    return nil, nil
+}
+
+func (r *Resolver) VisitUnary(expr *Unary) (Object, error) {
+    r.resolveExpr(expr.Right)
+    return nil, nil
+}
+
+func (r *Resolver) VisitUpdate(expr *Update) (Object, error) {
+    if variable, ok := expr.Target.(*Variable); ok {
+        r.checkNotConstant(variable.Name)
+    }
+    r.resolveExpr(expr.Value)
+    r.resolveExpr(expr.Target)
+    return nil, nil
+}
+
+func (r *Resolver) VisitVariable(expr *Variable) (Object, error) {
+    if len(r.scopes) > 0 {
+        if ready, ok := r.peekScope()[expr.Name.Lexeme]; ok && !ready {
+            TokenError(expr.Name, "Can't read local variable in its own initializer")
+        }
+    }
+
+    r.resolveLocal(expr, expr.Name)
+    return nil, nil
+}
+
+// HELPERS
+
+func (r *Resolver) resolveStmt(stmt Stmt) {
+    stmt.Accept(r)
+}
+
+func (r *Resolver) resolveExpr(expr Expr) {
+    expr.Accept(r)
+}
+
+// function to resolve a function body in a new scope holding its parameters
+func (r *Resolver) resolveFunction(function Function, ftype FunctionType) {
+    enclosingFunction := r.currentFunction
+    r.currentFunction = ftype
+
+    // a default is resolved before its own parameter is declared so it
+    // can only see the parameters to its left
+    r.beginScope()
+    for k, param := range function.Params {
+        if function.Defaults[k] != nil {
+            r.resolveExpr(function.Defaults[k])
+        }
+        r.declare(param)
+        r.define(param)
+    }
+    if function.Rest.Lexeme != "" {
+        r.declare(function.Rest)
+        r.define(function.Rest)
+    }
+    r.Resolve(function.Body)
+    r.endScope()
+
+    r.currentFunction = enclosingFunction
+}
+
+// function to find the innermost scope holding the name and
+// tell the interpreter how many scopes away it is
+// names not found in any scope are left for the globals
+func (r *Resolver) resolveLocal(expr Expr, name Token) {
+    for k := len(r.scopes) - 1; k >= 0; k-- {
+        if _, ok := r.scopes[k][name.Lexeme]; ok {
+            r.interpreter.Resolve(expr, len(r.scopes) - 1 - k)
+            return
+        }
+    }
+}
+
+// function to report an assignment to a constant the resolver can see
+// constants declared later at the top level are left for the interpreter
+func (r *Resolver) checkNotConstant(name Token) {
+    constant := r.globalConstants[name.Lexeme]
+    for k := len(r.scopes) - 1; k >= 0; k-- {
+        if _, ok := r.scopes[k][name.Lexeme]; ok {
+            constant = r.constants[k][name.Lexeme]
+            break
+        }
+    }
+
+    if constant {
+        TokenError(name, "Can't assign to constant '" + name.Lexeme + "'")
+    }
+}
+
+func (r *Resolver) beginScope() {
+    r.scopes = append(r.scopes, make(map[string]bool))
+    r.constants = append(r.constants, make(map[string]bool))
+}
+
+func (r *Resolver) endScope() {
+    r.scopes = r.scopes[:len(r.scopes) - 1]
+    r.constants = r.constants[:len(r.constants) - 1]
+}
+
+func (r *Resolver) peekScope() map[string]bool {
+    return r.scopes[len(r.scopes) - 1]
+}
+
+// function to add a name to the innermost scope, marked as not ready yet
+func (r *Resolver) declare(name Token) {
+    if len(r.scopes) == 0 {
+        // a global can be redefined, which also drops any "const"
+        delete(r.globalConstants, name.Lexeme)
+        return
+    }
+
+    scope := r.peekScope()
+    if _, ok := scope[name.Lexeme]; ok {
+        TokenError(name, "Already a variable with this name in this scope")
+    }
+    scope[name.Lexeme] = false
+}
+
+// function to mark a declared name as fully initialized
+func (r *Resolver) define(name Token) {
+    if len(r.scopes) == 0 {
+        return
+    }
+
+    r.peekScope()[name.Lexeme] = true
+}
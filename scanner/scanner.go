@@ -4,6 +4,8 @@ import (
     . "glox/util"
     . "glox/token"
     "strconv"
+    "strings"
+    "unicode/utf8"
 )
 
 type Scanner struct {
@@ -12,6 +14,9 @@ type Scanner struct {
     start int
     current int
     line int
+    // one entry per "${" currently open, counting the "{" nested inside it
+    // so the "}" that resumes the string can be told apart
+    interpolations []int
 }
 
 // Return a new "object"(read pointer) of type Scanner(read *Scanner) 
@@ -32,6 +37,10 @@ func (s *Scanner) ScanTokens() []Token {
         s.scanToken()
     }
 
+    if len(s.interpolations) > 0 {
+        Error(s.line, "Unterminated string interpolation.")
+    }
+
     s.tokens = append(s.tokens, NewToken(EOF, "", nil, s.line))
     return s.tokens
 }
@@ -45,21 +54,79 @@ func (s *Scanner) scanToken() {
     case ')':
         s.addToken(RIGHT_PAREN, nil)
     case '{':
+        if n := len(s.interpolations); n > 0 {
+            s.interpolations[n - 1]++
+        }
         s.addToken(LEFT_BRACE, nil)
     case '}':
+        if n := len(s.interpolations); n > 0 {
+            if s.interpolations[n - 1] == 0 {
+                // end of an interpolated expression, carry on with the string
+                s.interpolations = s.interpolations[:n - 1]
+                s.string()
+                return
+            }
+            s.interpolations[n - 1]--
+        }
         s.addToken(RIGHT_BRACE, nil)
+    case '[':
+        s.addToken(LEFT_BRACKET, nil)
+    case ']':
+        s.addToken(RIGHT_BRACKET, nil)
+    case ':':
+        s.addToken(COLON, nil)
     case ',':
         s.addToken(COMMA, nil)
     case '.':
-        s.addToken(DOT, nil)
+        if s.peek() == '.' && s.peekNext() == '.' {
+            s.current += 2
+            s.addToken(ELLIPSIS, nil)
+        } else {
+            s.addToken(DOT, nil)
+        }
     case '-':
-        s.addToken(MINUS, nil)
+        if s.match('=') {
+            s.addToken(MINUS_EQUAL, nil)
+        } else if s.match('-') {
+            s.addToken(MINUS_MINUS, nil)
+        } else {
+            s.addToken(MINUS, nil)
+        }
     case '+':
-        s.addToken(PLUS, nil)
+        if s.match('=') {
+            s.addToken(PLUS_EQUAL, nil)
+        } else if s.match('+') {
+            s.addToken(PLUS_PLUS, nil)
+        } else {
+            s.addToken(PLUS, nil)
+        }
     case ';':
         s.addToken(SEMICOLON, nil)
+    case '?':
+        if s.match('?') {
+            s.addToken(QUESTION_QUESTION, nil)
+        } else if s.match('.') {
+            s.addToken(QUESTION_DOT, nil)
+        } else {
+            s.addToken(QUESTION, nil)
+        }
     case '*':
-        s.addToken(STAR, nil)
+        if s.match('*') {
+            s.addToken(STAR_STAR, nil)
+        } else if s.match('=') {
+            s.addToken(STAR_EQUAL, nil)
+        } else {
+            s.addToken(STAR, nil)
+        }
+    case '%':
+        s.addToken(PERCENT, nil)
+    case '~':
+        // "//" is taken by comments so floor division is spelt "~/"
+        if s.match('/') {
+            s.addToken(TILDE_SLASH, nil)
+        } else {
+            Error(s.line, "Unexpected character.")
+        }
     case '!':
         if s.match('=') {
             s.addToken(BANG_EQUAL, nil)
@@ -69,6 +136,8 @@ func (s *Scanner) scanToken() {
     case '=':
         if s.match('=') {
             s.addToken(EQUAL_EQUAL, nil)
+        } else if s.match('>') {
+            s.addToken(ARROW, nil)
         } else {
             s.addToken(EQUAL, nil)
         }
@@ -89,6 +158,10 @@ func (s *Scanner) scanToken() {
             for s.peek() != '\n' && !s.isAtEnd() {
                 s.advance()
             }
+        } else if s.match('*') {
+            s.blockComment()
+        } else if s.match('=') {
+            s.addToken(SLASH_EQUAL, nil)
         } else {
             s.addToken(SLASH, nil)
         }
@@ -107,12 +180,37 @@ func (s *Scanner) scanToken() {
             s.number()
         } else if IsAlpha(c) {
             s.identifier()
+        } else if !utf8.ValidString(s.src[s.start:s.current]) {
+            Error(s.line, "Invalid UTF-8 encoding.")
         } else {
             Error(s.line, "Unexpected character.")
         }
     }
 }
 
+// Function to skip a block comment, the opening "/*" has already been consumed
+// block comments nest so a region that already has comments can be commented out
+func (s *Scanner) blockComment() {
+    startLine := s.line
+    depth := 1
+    for depth > 0 {
+        if s.isAtEnd() {
+            Error(startLine, "Unterminated block comment.")
+            return
+        }
+
+        if s.peek() == '/' && s.peekNext() == '*' {
+            s.current += 2
+            depth++
+        } else if s.peek() == '*' && s.peekNext() == '/' {
+            s.current += 2
+            depth--
+        } else if s.advance() == '\n' {
+            s.line++
+        }
+    }
+}
+
 // Function to finish scanning an identifier. It checks if the text maps to 
 // an existing TokenType else is NO_TYPE and assigns it as IDENTIFIER
 func (s *Scanner) identifier() {
@@ -129,13 +227,29 @@ func (s *Scanner) identifier() {
     s.addToken(tType, nil)
 }
 
-// Function to finish scanning a string 
+// Function to finish scanning a string. The opening quote, or the "}"
+// ending an interpolated expression, has already been consumed
 func (s *Scanner) string() {
+    defer s.validateString()
+
+    var value strings.Builder
     for s.peek() != '"' && !s.isAtEnd() {
-        if s.peek() == '\n' {
+        c := s.advance()
+        switch {
+        case c == '\n':
             s.line++
+            value.WriteRune(c)
+        case c == '\\':
+            s.escape(&value)
+        case c == '$' && s.peek() == '{':
+            // Eat the '{' and emit the segment so far
+            s.advance()
+            s.addToken(INTERPOLATION, value.String())
+            s.interpolations = append(s.interpolations, 0)
+            return
+        default:
+            value.WriteRune(c)
         }
-        s.advance()
     }
 
     if s.isAtEnd() {
@@ -146,9 +260,77 @@ func (s *Scanner) string() {
     // Eat closing quote
     s.advance()
 
-    // Trim quotes
-    value := s.src[s.start + 1:s.current - 1]
-    s.addToken(STRING, value)
+    s.addToken(STRING, value.String())
+}
+
+// Function to check the raw source of the string just scanned is valid UTF-8
+func (s *Scanner) validateString() {
+    if !utf8.ValidString(s.src[s.start:s.current]) {
+        Error(s.line, "Invalid UTF-8 encoding in string.")
+    }
+}
+
+// Function to translate the escape sequence following a backslash
+func (s *Scanner) escape(value *strings.Builder) {
+    if s.isAtEnd() {
+        Error(s.line, "Unterminated escape sequence.")
+        return
+    }
+
+    c := s.advance()
+    switch c {
+    case 'n':
+        value.WriteByte('\n')
+    case 't':
+        value.WriteByte('\t')
+    case 'r':
+        value.WriteByte('\r')
+    case '0':
+        value.WriteByte(0)
+    case '\\', '"', '$':
+        value.WriteRune(c)
+    case 'u':
+        s.unicodeEscape(value)
+    default:
+        Error(s.line, "Invalid escape sequence '\\" + string(c) + "'.")
+    }
+}
+
+// Function to translate a unicode escape, either \uXXXX or \u{X...}
+func (s *Scanner) unicodeEscape(value *strings.Builder) {
+    var digits string
+    if s.match('{') {
+        start := s.current
+        for s.peek() != '}' && IsHexDigit(s.peek()) {
+            s.advance()
+        }
+        digits = s.src[start:s.current]
+
+        if !s.match('}') || len(digits) == 0 || len(digits) > 6 {
+            Error(s.line, "Invalid unicode escape sequence.")
+            return
+        }
+    } else {
+        start := s.current
+        for k := 0; k < 4 && IsHexDigit(s.peek()); k++ {
+            s.advance()
+        }
+        digits = s.src[start:s.current]
+
+        if len(digits) != 4 {
+            Error(s.line, "Invalid unicode escape sequence.")
+            return
+        }
+    }
+
+    code, err := strconv.ParseUint(digits, 16, 32)
+    r := rune(code)
+    if err != nil || !utf8.ValidRune(r) {
+        Error(s.line, "Invalid unicode code point '" + digits + "'.")
+        return
+    }
+
+    value.WriteRune(r)
 }
 
 // Function to finish scanning a number
@@ -164,40 +346,60 @@ func (s *Scanner) number() {
         for IsDigit(s.peek()) {
             s.advance()
         }
+
+        // Convert string -> double
+        f, err := strconv.ParseFloat(s.src[s.start : s.current], 64)
+        Check(err)
+        s.addToken(NUMBER, f)
+        return
     }
 
-    // Convert string -> double
-    f, err := strconv.ParseFloat(s.src[s.start : s.current], 64)
-    Check(err)
-    s.addToken(NUMBER, f)
+    // No fraction, convert string -> integer
+    n, err := strconv.ParseInt(s.src[s.start : s.current], 10, 64)
+    if err != nil {
+        // too big for an int64, fall back to a float like overflowing arithmetic
+        f, err := strconv.ParseFloat(s.src[s.start : s.current], 64)
+        Check(err)
+        s.addToken(NUMBER, f)
+        return
+    }
+    s.addToken(NUMBER, n)
 }
 
-// Function to check if the next byte matches the expected byte
-func (s *Scanner) match(expected byte) bool {
-    if s.isAtEnd() || s.src[s.current] != expected {
+// Function to check if the next rune matches the expected rune
+func (s *Scanner) match(expected rune) bool {
+    if s.isAtEnd() || s.peek() != expected {
         return false
     }
 
-    s.current++
+    s.advance()
     return true
 } 
 
-// Function to peek at the next byte without advancing
-func (s *Scanner) peek() byte {
+// Function to peek at the next rune without advancing
+// invalid UTF-8 is returned as utf8.RuneError
+func (s *Scanner) peek() rune {
     if s.isAtEnd() {
         return 0
     }
 
-    return s.src[s.current]
+    r, _ := utf8.DecodeRuneInString(s.src[s.current:])
+    return r
 }
 
-// Function to peek at the next to next byte without advancing
-func (s *Scanner) peekNext() byte {
-    if s.current + 1 >= len(s.src) {
+// Function to peek at the next to next rune without advancing
+func (s *Scanner) peekNext() rune {
+    if s.isAtEnd() {
+        return 0
+    }
+
+    _, size := utf8.DecodeRuneInString(s.src[s.current:])
+    if s.current + size >= len(s.src) {
         return 0
     }
 
-    return s.src[s.current + 1]
+    r, _ := utf8.DecodeRuneInString(s.src[s.current + size:])
+    return r
 }
 
 // Function to check if source string is at the end
@@ -205,11 +407,11 @@ func (s *Scanner) isAtEnd() bool {
     return s.current >= len(s.src)
 }
 
-// Function to return the current byte and move the current index forward
-func (s *Scanner) advance() byte {
-    ret := s.src[s.current]
-    s.current++
-    return ret
+// Function to return the current rune and move the current index past it
+func (s *Scanner) advance() rune {
+    r, size := utf8.DecodeRuneInString(s.src[s.current:])
+    s.current += size
+    return r
 }
 
 // Function to add a token to the tokens slice 
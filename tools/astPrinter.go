@@ -17,15 +17,15 @@ func (a AstPrinter) Print(expr Expr) string {
 
 // Defintions for visitor functions
 
-func (a AstPrinter) VisitBinary(expr Binary) (Object, error) {
+func (a AstPrinter) VisitBinary(expr *Binary) (Object, error) {
     return a.parenthesize(expr.Operator.Lexeme, expr.Left, expr.Right), nil
 }
 
-func (a AstPrinter) VisitGrouping(expr Grouping) (Object, error) {
+func (a AstPrinter) VisitGrouping(expr *Grouping) (Object, error) {
     return a.parenthesize("group", expr.Expression), nil
 }
 
-func (a AstPrinter) VisitLiteral(expr Literal) (Object, error) {
+func (a AstPrinter) VisitLiteral(expr *Literal) (Object, error) {
     if expr.Value == nil {
         return "nil", nil
     }
@@ -33,7 +33,7 @@ func (a AstPrinter) VisitLiteral(expr Literal) (Object, error) {
     return ret, nil
 }
 
-func (a AstPrinter) VisitUnary(expr Unary) (Object, error) {
+func (a AstPrinter) VisitUnary(expr *Unary) (Object, error) {
     return a.parenthesize(expr.Operator.Lexeme, expr.Right), nil
 }
 
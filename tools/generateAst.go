@@ -18,24 +18,49 @@ func main() {
 
     defineAst(outputDir, "Expr", map[string][]string {
         "Assign": {"Name Token", "Value Expr"},
+        "AssignPattern": {"Opening Token", "Targets []Expr", "Rest Expr", "Value Expr"},
         "Binary": {"Left Expr", "Operator Token", "Right Expr"},
         "Call": {"Callee Expr", "Paren Token", "Arguments []Expr"},
+        "Conditional": {"Condition Expr", "ThenBranch Expr", "ElseBranch Expr"},
+        "Get": {"Object Expr", "Name Token"},
         "Grouping": {"Expression Expr"},
+        "Interpolation": {"Parts []Expr"},
+        "Lambda": {"Keyword Token", "Declaration Function"},
+        "List": {"Bracket Token", "Elements []Expr"},
         "Literal": {"Value Object"},
         "Logical": {"Left Expr", "Operator Token", "Right Expr"},
+        "Map": {"Brace Token", "Keys []Expr", "Values []Expr"},
+        "Optional": {"Object Expr"},
+        "OptionalChain": {"Expression Expr"},
+        "Set": {"Object Expr", "Name Token", "Value Expr"},
+        "SetSubscript": {"Object Expr", "Bracket Token", "Index Expr", "Value Expr"},
+        "Subscript": {"Object Expr", "Bracket Token", "Index Expr"},
+        "Super": {"Keyword Token", "Method Token"},
+        "This": {"Keyword Token"},
         "Unary": {"Operator Token", "Right Expr"},
+        "Update": {"Target Expr", "Operator Token", "Value Expr", "Postfix bool"},
         "Variable": {"Name Token"},
     })
     
     defineAst(outputDir, "Stmt", map[string][]string {
         "Block": {"Statements []Stmt"},
+        "Break": {"Keyword Token"},
+        "Class": {"Name Token", "Superclass Expr", "Methods []Function", "Getters []Function", "StaticMethods []Function"},
         "StmtExpression": {"Expression Expr"},
-        "Function": {"Name Token", "Params []Token", "Body []Stmt"},
+        "Const": {"Name Token", "Initializer Expr"},
+        "Continue": {"Keyword Token"},
+        "ForIn": {"Name Token", "Keyword Token", "Iterable Expr", "Body Stmt"},
+        "Function": {"Name Token", "Params []Token", "Defaults []Expr", "Rest Token", "Body []Stmt"},
         "If": {"Condition Expr", "ThenBranch Stmt", "ElseBranch Stmt"},
+        "Import": {"Keyword Token", "Path Token", "Name Token"},
+        "Match": {"Keyword Token", "Subject Expr", "Patterns [][]Expr", "Guards []Expr", "Bodies []Stmt", "Default Stmt"},
         "Print": {"Expression Expr"},
-        "Return": {"Keyword Token", "Value Expr"},
+        "Return": {"Keyword Token", "Value Expr", "TailCall bool"},
+        "Throw": {"Keyword Token", "Value Expr"},
+        "Try": {"Body Stmt", "CatchName Token", "CatchBody Stmt", "FinallyBody Stmt"},
+        "VarPattern": {"Opening Token", "Names []Token", "Rest Token", "Initializer Expr"},
         "Var": {"Name Token", "Initializer Expr"},
-        "While": {"Condition Expr", "Body Stmt"},
+        "While": {"Condition Expr", "Body Stmt", "Increment Expr"},
     })
 }
 
@@ -69,8 +94,15 @@ func defineAst(outputDir, baseName string, rules map[string][]string) {
         fp.WriteString("\t. \"glox/util\"\n")
         fp.WriteString(")\n\n")
     }
+    // Expr nodes are handed around as pointers so each node has an identity
+    // the resolver can key its scope distances on
+    ref := ""
+    if (baseName == "Expr") {
+        ref = "*"
+    }
+
     // create visitor interface
-    defineVisitor(fp, baseName, rules)
+    defineVisitor(fp, baseName, ref, rules)
 
 
     // create the base class
@@ -80,23 +112,23 @@ func defineAst(outputDir, baseName string, rules map[string][]string) {
 
     // create all the types
     for className, fields := range rules {
-        defineType(fp, baseName, className, fields)
+        defineType(fp, baseName, ref, className, fields)
     }
 }
 
 // Function to create the visitor interface and all the functions to implement
-func defineVisitor(fp *os.File, baseName string, rules map[string][]string) {
+func defineVisitor(fp *os.File, baseName, ref string, rules map[string][]string) {
     fp.WriteString("type " + baseName + "Visitor interface {\n")
     
     for className, _ := range rules {
-        fp.WriteString("\tVisit" + className + "(obj " + className + ") (Object, error)\n")
+        fp.WriteString("\tVisit" + className + "(obj " + ref + className + ") (Object, error)\n")
     }
 
     fp.WriteString("}\n\n")
 }
 
 // Function to create a new type for a specified class
-func defineType(fp *os.File, baseName, className string, fields []string) {
+func defineType(fp *os.File, baseName, ref, className string, fields []string) {
     // write type
     fp.WriteString("type " + className + " struct {\n")
 
@@ -116,9 +148,9 @@ func defineType(fp *os.File, baseName, className string, fields []string) {
         }
     }
 
-    fp.WriteString(") " + className + " {\n")
+    fp.WriteString(") " + ref + className + " {\n")
 
-    fp.WriteString("\treturn " + className + "{")
+    fp.WriteString("\treturn " + strings.Replace(ref, "*", "&", 1) + className + "{")
 
     for i, field := range fields {
         slice := strings.Split(field, " ")
@@ -131,7 +163,7 @@ func defineType(fp *os.File, baseName, className string, fields []string) {
     fp.WriteString("}\n}\n\n")
 
     // write accept
-    fp.WriteString("func (obj " + className + ") Accept(v " + baseName + "Visitor) (Object, error) {\n")
+    fp.WriteString("func (obj " + ref + className + ") Accept(v " + baseName + "Visitor) (Object, error) {\n")
     fp.WriteString("\treturn v.Visit" + className + "(obj)\n")
     fp.WriteString("}\n\n")
 }
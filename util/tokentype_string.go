@@ -13,46 +13,75 @@ func _() {
 	_ = x[RIGHT_PAREN-2]
 	_ = x[LEFT_BRACE-3]
 	_ = x[RIGHT_BRACE-4]
-	_ = x[COMMA-5]
-	_ = x[DOT-6]
-	_ = x[MINUS-7]
-	_ = x[PLUS-8]
-	_ = x[SEMICOLON-9]
-	_ = x[SLASH-10]
-	_ = x[STAR-11]
-	_ = x[BANG-12]
-	_ = x[BANG_EQUAL-13]
-	_ = x[EQUAL-14]
-	_ = x[EQUAL_EQUAL-15]
-	_ = x[GREAT-16]
-	_ = x[GREAT_EQUAL-17]
-	_ = x[LESS-18]
-	_ = x[LESS_EQUAL-19]
-	_ = x[IDENTIFIER-20]
-	_ = x[STRING-21]
-	_ = x[NUMBER-22]
-	_ = x[AND-23]
-	_ = x[CLASS-24]
-	_ = x[ELSE-25]
-	_ = x[FALSE-26]
-	_ = x[FUN-27]
-	_ = x[FOR-28]
-	_ = x[IF-29]
-	_ = x[NIL-30]
-	_ = x[OR-31]
-	_ = x[PRINT-32]
-	_ = x[RETURN-33]
-	_ = x[SUPER-34]
-	_ = x[THIS-35]
-	_ = x[TRUE-36]
-	_ = x[VAR-37]
-	_ = x[WHILE-38]
-	_ = x[EOF-39]
+	_ = x[LEFT_BRACKET-5]
+	_ = x[RIGHT_BRACKET-6]
+	_ = x[COLON-7]
+	_ = x[COMMA-8]
+	_ = x[DOT-9]
+	_ = x[ELLIPSIS-10]
+	_ = x[MINUS-11]
+	_ = x[PLUS-12]
+	_ = x[SEMICOLON-13]
+	_ = x[QUESTION-14]
+	_ = x[SLASH-15]
+	_ = x[STAR-16]
+	_ = x[PERCENT-17]
+	_ = x[BANG-18]
+	_ = x[BANG_EQUAL-19]
+	_ = x[EQUAL-20]
+	_ = x[EQUAL_EQUAL-21]
+	_ = x[ARROW-22]
+	_ = x[GREAT-23]
+	_ = x[GREAT_EQUAL-24]
+	_ = x[LESS-25]
+	_ = x[LESS_EQUAL-26]
+	_ = x[STAR_STAR-27]
+	_ = x[TILDE_SLASH-28]
+	_ = x[PLUS_EQUAL-29]
+	_ = x[MINUS_EQUAL-30]
+	_ = x[STAR_EQUAL-31]
+	_ = x[SLASH_EQUAL-32]
+	_ = x[PLUS_PLUS-33]
+	_ = x[MINUS_MINUS-34]
+	_ = x[QUESTION_QUESTION-35]
+	_ = x[QUESTION_DOT-36]
+	_ = x[IDENTIFIER-37]
+	_ = x[STRING-38]
+	_ = x[INTERPOLATION-39]
+	_ = x[NUMBER-40]
+	_ = x[AND-41]
+	_ = x[BREAK-42]
+	_ = x[CASE-43]
+	_ = x[CATCH-44]
+	_ = x[CLASS-45]
+	_ = x[CONST-46]
+	_ = x[CONTINUE-47]
+	_ = x[DEFAULT-48]
+	_ = x[ELSE-49]
+	_ = x[FALSE-50]
+	_ = x[FINALLY-51]
+	_ = x[FUN-52]
+	_ = x[FOR-53]
+	_ = x[IF-54]
+	_ = x[IMPORT-55]
+	_ = x[MATCH-56]
+	_ = x[NIL-57]
+	_ = x[OR-58]
+	_ = x[PRINT-59]
+	_ = x[RETURN-60]
+	_ = x[SUPER-61]
+	_ = x[THIS-62]
+	_ = x[THROW-63]
+	_ = x[TRUE-64]
+	_ = x[TRY-65]
+	_ = x[VAR-66]
+	_ = x[WHILE-67]
+	_ = x[EOF-68]
 }
 
-const _TokenType_name = "NO_TYPELEFT_PARENRIGHT_PARENLEFT_BRACERIGHT_BRACECOMMADOTMINUSPLUSSEMICOLONSLASHSTARBANGBANG_EQUALEQUALEQUAL_EQUALGREATGREAT_EQUALLESSLESS_EQUALIDENTIFIERSTRINGNUMBERANDCLASSELSEFALSEFUNFORIFNILORPRINTRETURNSUPERTHISTRUEVARWHILEEOF"
+const _TokenType_name = "NO_TYPELEFT_PARENRIGHT_PARENLEFT_BRACERIGHT_BRACELEFT_BRACKETRIGHT_BRACKETCOLONCOMMADOTELLIPSISMINUSPLUSSEMICOLONQUESTIONSLASHSTARPERCENTBANGBANG_EQUALEQUALEQUAL_EQUALARROWGREATGREAT_EQUALLESSLESS_EQUALSTAR_STARTILDE_SLASHPLUS_EQUALMINUS_EQUALSTAR_EQUALSLASH_EQUALPLUS_PLUSMINUS_MINUSQUESTION_QUESTIONQUESTION_DOTIDENTIFIERSTRINGINTERPOLATIONNUMBERANDBREAKCASECATCHCLASSCONSTCONTINUEDEFAULTELSEFALSEFINALLYFUNFORIFIMPORTMATCHNILORPRINTRETURNSUPERTHISTHROWTRUETRYVARWHILEEOF"
 
-var _TokenType_index = [...]uint8{0, 7, 17, 28, 38, 49, 54, 57, 62, 66, 75, 80, 84, 88, 98, 103, 114, 119, 130, 134, 144, 154, 160, 166, 169, 174, 178, 183, 186, 189, 191, 194, 196, 201, 207, 212, 216, 220, 223, 228, 231}
+var _TokenType_index = [...]uint16{0, 7, 17, 28, 38, 49, 61, 74, 79, 84, 87, 95, 100, 104, 113, 121, 126, 130, 137, 141, 151, 156, 167, 172, 177, 188, 192, 202, 211, 222, 232, 243, 253, 264, 273, 284, 301, 313, 323, 329, 342, 348, 351, 356, 360, 365, 370, 375, 383, 390, 394, 399, 406, 409, 412, 414, 420, 425, 428, 430, 435, 441, 446, 450, 455, 459, 462, 465, 470, 473}
 
 func (i TokenType) String() string {
 	if i < 0 || i >= TokenType(len(_TokenType_index)-1) {
@@ -15,44 +15,74 @@ const (
     RIGHT_PAREN
     LEFT_BRACE
     RIGHT_BRACE
+    LEFT_BRACKET
+    RIGHT_BRACKET
+    COLON
     COMMA
     DOT
+    ELLIPSIS
     MINUS
     PLUS
     SEMICOLON
+    QUESTION
     SLASH
     STAR
+    PERCENT
 
     // One or two byte tokens
     BANG
     BANG_EQUAL
     EQUAL
     EQUAL_EQUAL
+    ARROW
     GREAT
     GREAT_EQUAL
     LESS
     LESS_EQUAL
+    STAR_STAR
+    TILDE_SLASH
+    PLUS_EQUAL
+    MINUS_EQUAL
+    STAR_EQUAL
+    SLASH_EQUAL
+    PLUS_PLUS
+    MINUS_MINUS
+    QUESTION_QUESTION
+    QUESTION_DOT
 
     // Literals
     IDENTIFIER
     STRING
+    // string segment that ends at a "${" inside an interpolated string
+    INTERPOLATION
     NUMBER
 
     // Keywords
     AND
+    BREAK
+    CASE
+    CATCH
     CLASS
+    CONST
+    CONTINUE
+    DEFAULT
     ELSE
     FALSE
+    FINALLY
     FUN
     FOR
     IF
+    IMPORT
+    MATCH
     NIL
     OR
     PRINT
     RETURN
     SUPER
     THIS
+    THROW
     TRUE
+    TRY
     VAR
     WHILE
 
@@ -71,19 +101,30 @@ type Object interface{}
 // keywords to recognise and map tokens to
 var Keywords = map[string]TokenType{
     "and": AND,
+    "break": BREAK,
+    "case": CASE,
+    "catch": CATCH,
     "class": CLASS,
+    "const": CONST,
+    "continue": CONTINUE,
+    "default": DEFAULT,
     "else": ELSE,
     "false": FALSE,
+    "finally": FINALLY,
     "for": FOR,
     "fun": FUN,
     "if": IF,
+    "import": IMPORT,
+    "match": MATCH,
     "nil": NIL,
     "or": OR,
     "print": PRINT,
     "return": RETURN,
     "super": SUPER,
     "this": THIS,
+    "throw": THROW,
     "true": TRUE,
+    "try": TRY,
     "var": VAR,
     "while": WHILE,
 }
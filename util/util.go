@@ -3,6 +3,7 @@ package util
 import (
     "fmt"
     "os"
+    "unicode"
 )
 
 // Global vars used to stop line/program from being run
@@ -32,15 +33,20 @@ func Report(line int, where string, msg string) {
 }
 
 // Util functions to check if characters are alphabets/digits
+// letters are any unicode letter, digits in number literals stay ASCII
 
-func IsAlpha(c byte) bool {
-    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c == '_')
+func IsAlpha(c rune) bool {
+    return unicode.IsLetter(c) || (c == '_')
 }
 
-func IsAlphaNumeric(c byte) bool {
-    return IsAlpha(c) || IsDigit(c)
+func IsAlphaNumeric(c rune) bool {
+    return IsAlpha(c) || unicode.IsDigit(c) || unicode.IsMark(c)
 }
 
-func IsDigit(c byte) bool {
+func IsDigit(c rune) bool {
     return c >= '0' && c <= '9'
 }
+
+func IsHexDigit(c rune) bool {
+    return IsDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}